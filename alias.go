@@ -0,0 +1,275 @@
+package config
+
+import (
+	"maps"
+	"sort"
+
+	"dario.cat/mergo"
+)
+
+// DeprecateFunc the warning func on found deprecated key in the loaded data. see Config.Deprecate()
+type DeprecateFunc func(oldKey, newKey, msg string)
+
+// keyAlias an alias of the config key
+type keyAlias struct {
+	// the alias key path. eg: "db.hostname"
+	alias string
+	keys  []string
+	// the target key path. eg: "db.host"
+	target     string
+	targetKeys []string
+	// is deprecated key, see Config.Deprecate()
+	deprecated bool
+	msg        string
+}
+
+// AliasKey add an alias for the config key. see Config.AliasKey()
+func AliasKey(alias, key string) { dc.AliasKey(alias, key) }
+
+// AliasKey add an alias for the config key. use it for rename the config key, and keep the old key working.
+//
+// The value of the alias key in the loaded data will be moved to the key, if the key is not exists.
+// If both are maps, they will be merged and the values of the key are kept.
+// The move of the loaded data will fire the OnSetData event.
+// Get, Set, Exists and Structure by the alias key will use the key instead.
+//
+// NOTE: the alias key can be the parent path. eg: alias "database" for "db", "database.host" will be "db.host"
+//
+// Usage:
+//
+//	c.AliasKey("db.hostname", "db.host")
+//	// old config file: {"db": {"hostname": "localhost"}}
+//	c.String("db.host")     // "localhost"
+//	c.String("db.hostname") // "localhost"
+func (c *Config) AliasKey(alias, key string) { c.addKeyAlias(alias, key, false, "") }
+
+// Deprecate mark the old key is deprecated and replaced by the new key. see Config.Deprecate()
+func Deprecate(oldKey, newKey, msg string) { dc.Deprecate(oldKey, newKey, msg) }
+
+// Deprecate mark the old key is deprecated and replaced by the new key. the old key is an alias of the new key, see AliasKey()
+//
+// The Options.DeprecateFunc will be called once per deprecated key found in the loaded data.
+//
+// Usage:
+//
+//	c := config.New("app", config.WithDeprecateFunc(func(oldKey, newKey, msg string) {
+//		log.Printf("config key '%s' is deprecated, please use '%s'. %s", oldKey, newKey, msg)
+//	}))
+//	c.Deprecate("log_level", "log.level", "will be removed in v3")
+func (c *Config) Deprecate(oldKey, newKey, msg string) { c.addKeyAlias(oldKey, newKey, true, msg) }
+
+func (c *Config) addKeyAlias(alias, key string, deprecated bool, msg string) {
+	if c.root != nil {
+		c.root.addKeyAlias(c.scopeKey(alias, true), c.scopeKey(key, true), deprecated, msg)
+		return
+	}
+
+	aliasKeys, err := c.parseKeys(alias)
+	if err != nil {
+		c.addError(err)
+		return
+	}
+
+	targetKeys, err := c.parseKeys(key)
+	if err != nil {
+		c.addError(err)
+		return
+	}
+
+	sep := c.opts.Delimiter
+	ka := &keyAlias{
+		alias:      keysToPath(aliasKeys, sep),
+		keys:       aliasKeys,
+		target:     keysToPath(targetKeys, sep),
+		targetKeys: targetKeys,
+		deprecated: deprecated,
+		msg:        msg,
+	}
+	if ka.alias == ka.target {
+		return
+	}
+
+	c.lock.Lock()
+	aliases := maps.Clone(c.aliases())
+	if aliases == nil {
+		aliases = make(map[string]*keyAlias)
+	}
+	aliases[ka.alias] = ka
+	c.keyAliases.Store(&aliases)
+
+	// migrate the loaded data on a copy, if the alias key exists.
+	var found []*keyAlias
+	old := c.getData()
+	if _, err := findByKeys(old, ka.keys, 0, sep); err == nil {
+		data := deepCopy(old).(map[string]any)
+		found = c.migrateAliases(data)
+		c.storeData(data)
+		c.ClearCaches()
+		c.queueEvent(OnSetData, nil, c.collectChanges(OnSetData, nil, old, true, data, true))
+	}
+	c.lock.Unlock()
+
+	c.warnDeprecated(found)
+	c.flushEvents()
+}
+
+// parse the key path and normalize the keys by Options.KeyMatch
+func (c *Config) parseKeys(key string) ([]string, error) {
+	sep := c.opts.Delimiter
+	if key = formatKey(key, string(sep)); key == "" {
+		return nil, ErrKeyIsEmpty
+	}
+
+	keys, err := parsePath(key, sep)
+	if err != nil {
+		return nil, err
+	}
+	return c.normKeys(keys), nil
+}
+
+// get the key aliases. it is immutable, AliasKey() will publish a new one.
+func (c *Config) aliases() map[string]*keyAlias {
+	if p := c.keyAliases.Load(); p != nil {
+		return *p
+	}
+	return nil
+}
+
+// get the key aliases relative to the scope view, the aliases out of the scope are dropped.
+func (c *Config) scopeAliases() map[string]*keyAlias {
+	if c.root == nil {
+		return c.aliases()
+	}
+
+	n := len(c.prefix)
+	sep := c.root.opts.Delimiter
+	var aliases map[string]*keyAlias
+	for _, ka := range c.root.aliases() {
+		if len(ka.keys) <= n || len(ka.targetKeys) <= n || !hasKeysPrefix(ka.keys, c.prefix) || !hasKeysPrefix(ka.targetKeys, c.prefix) {
+			continue
+		}
+
+		sub := *ka
+		sub.keys, sub.targetKeys = ka.keys[n:], ka.targetKeys[n:]
+		sub.alias, sub.target = keysToPath(sub.keys, sep), keysToPath(sub.targetKeys, sep)
+		if aliases == nil {
+			aliases = make(map[string]*keyAlias)
+		}
+		aliases[sub.alias] = &sub
+	}
+	return aliases
+}
+
+// resolve the alias key path to the target key path. returns the key if it is not an alias.
+func (c *Config) resolveAlias(key string) string {
+	if len(c.aliases()) == 0 {
+		return key
+	}
+
+	sep := c.opts.Delimiter
+	keys, err := parsePath(key, sep)
+	if err != nil {
+		return key
+	}
+
+	if keys, ok := c.resolveAliasKeys(c.normKeys(keys)); ok {
+		return keysToPath(keys, sep)
+	}
+	return key
+}
+
+// resolve the alias keys to the target keys. will use the longest alias if multi matched.
+//
+// Support chained aliases. eg: "db.hostname" -> "db.host_name" -> "db.host"
+func (c *Config) resolveAliasKeys(keys []string) ([]string, bool) {
+	var resolved bool
+	aliases := c.aliases()
+	for i := 0; i < len(aliases); i++ {
+		var match *keyAlias
+		for _, ka := range aliases {
+			if hasKeysPrefix(keys, ka.keys) && (match == nil || len(ka.keys) > len(match.keys)) {
+				match = ka
+			}
+		}
+
+		if match == nil {
+			break
+		}
+
+		resolved = true
+		newKeys := make([]string, 0, len(match.targetKeys)+len(keys)-len(match.keys))
+		newKeys = append(newKeys, match.targetKeys...)
+		keys = append(newKeys, keys[len(match.keys):]...)
+	}
+	return keys, resolved
+}
+
+// move the values of the alias keys to the target keys in the data.
+// returns the deprecated aliases found in the data, which are not warned yet.
+func (c *Config) migrateAliases(data map[string]any) (found []*keyAlias) {
+	kas := c.aliases()
+	if len(kas) == 0 || len(data) == 0 {
+		return
+	}
+
+	aliases := make([]string, 0, len(kas))
+	for alias := range kas {
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+
+	sep := c.opts.Delimiter
+	for _, alias := range aliases {
+		ka := kas[alias]
+		val, err := findByKeys(data, ka.keys, 0, sep)
+		if err != nil {
+			continue
+		}
+
+		deleteByKeys(data, toPathKeys(ka.keys), 0)
+
+		targetKeys, _ := c.resolveAliasKeys(ka.targetKeys)
+		old, err := findByKeys(data, targetKeys, 0, sep)
+		if err != nil {
+			_, _ = setByKeys(data, toPathKeys(targetKeys), 0, val, sep)
+		} else if oldMp, ok := old.(map[string]any); ok {
+			// merge the map value, the values of the target key are kept.
+			if mp, ok := val.(map[string]any); ok {
+				_ = mergo.Merge(&oldMp, mp)
+			}
+		}
+
+		if ka.deprecated && !c.warnedKeys[alias] {
+			if c.warnedKeys == nil {
+				c.warnedKeys = make(map[string]bool)
+			}
+			c.warnedKeys[alias] = true
+			found = append(found, ka)
+		}
+	}
+	return
+}
+
+// call the Options.DeprecateFunc for the found deprecated keys
+func (c *Config) warnDeprecated(found []*keyAlias) {
+	if c.opts.DeprecateFunc == nil {
+		return
+	}
+
+	for _, ka := range found {
+		c.opts.DeprecateFunc(ka.alias, ka.target, ka.msg)
+	}
+}
+
+func hasKeysPrefix(keys, prefix []string) bool {
+	if len(keys) < len(prefix) {
+		return false
+	}
+
+	for i, key := range prefix {
+		if keys[i] != key {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,133 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/gookit/goutil/testutil/assert"
+)
+
+func TestConfig_AliasKey(t *testing.T) {
+	is := assert.New(t)
+	c := New("test")
+	c.AliasKey("db.hostname", "db.host")
+	c.AliasKey("database", "db")
+
+	// old config content
+	err := c.LoadStrings(JSON, `{"db": {"hostname": "localhost", "port": 3306}, "database": {"user": "root"}}`)
+	is.NoErr(err)
+
+	// the values are moved to the new keys
+	is.Eq(map[string]any{"host": "localhost", "port": float64(3306), "user": "root"}, c.Get("db"))
+	is.NotContains(c.Data(), "database")
+
+	// read by new key and alias key
+	is.Eq("localhost", c.String("db.host"))
+	is.Eq("localhost", c.String("db.hostname"))
+	is.Eq("localhost", c.String("database.hostname"))
+	is.Eq("root", c.String("database.user"))
+	is.True(c.Exists("database.port"))
+
+	// set by alias key
+	is.NoErr(c.Set("database.hostname", "127.0.0.1"))
+	is.Eq("127.0.0.1", c.String("db.host"))
+
+	// structure
+	type Db struct {
+		Host string
+		Port int
+		User string
+	}
+	db := &Db{}
+	is.NoErr(c.Structure("database", db))
+	is.Eq(Db{Host: "127.0.0.1", Port: 3306, User: "root"}, *db)
+
+	// the new key is exists, will not override it.
+	c = New("test")
+	is.NoErr(c.LoadStrings(JSON, `{"db": {"hostname": "old", "host": "new"}}`))
+	c.AliasKey("db.hostname", "db.host")
+	is.Eq("new", c.String("db.host"))
+	is.NotContains(c.Data()["db"], "hostname")
+
+	// chained aliases
+	c = New("test")
+	c.AliasKey("db.hostname", "db.host_name")
+	c.AliasKey("db.host_name", "db.host")
+	is.NoErr(c.LoadStrings(JSON, `{"db": {"hostname": "localhost"}}`))
+	is.Eq("localhost", c.String("db.host"))
+	is.Eq("localhost", c.String("db.host_name"))
+
+	// invalid key
+	c.AliasKey("", "db")
+	is.ErrIs(c.Error(), ErrKeyIsEmpty)
+}
+
+func TestConfig_AliasKey_migrate(t *testing.T) {
+	is := assert.New(t)
+
+	// SetData don't change the source data
+	c := New("test")
+	c.AliasKey("db.hostname", "db.host")
+	src := map[string]any{"db": map[string]any{"hostname": "localhost"}}
+	c.SetData(src)
+	is.Eq("localhost", c.String("db.host"))
+	is.Eq(map[string]any{"hostname": "localhost"}, src["db"])
+
+	// add alias after loaded: fire the event, and the caches are cleared
+	var events []string
+	var changes []string
+	c = New("test", EnableCache, WithHookFunc(func(event string, c *Config) {
+		events = append(events, event)
+	}))
+	is.NoErr(c.LoadStrings(JSON, `{"db": {"hostname": "localhost"}, "name": "app"}`))
+	c.OnChange("db", func(ev ChangeEvent) {
+		changes = append(changes, ev.Path)
+	})
+	is.Eq("", c.String("db.host"))
+	events = nil
+
+	c.AliasKey("db.hostname", "db.host")
+	is.Eq("localhost", c.String("db.host"))
+	is.Eq([]string{OnSetData}, events)
+	is.Eq([]string{"db.host", "db.hostname"}, changes)
+
+	// the alias key not exists in the data, no event.
+	c.AliasKey("app_name", "name")
+	is.Eq([]string{OnSetData}, events)
+	is.Eq("app", c.String("app_name"))
+}
+
+func TestConfig_Deprecate(t *testing.T) {
+	is := assert.New(t)
+
+	var warns []string
+	c := New("test", WithDeprecateFunc(func(oldKey, newKey, msg string) {
+		warns = append(warns, oldKey+" -> "+newKey+": "+msg)
+	}))
+	c.Deprecate("log_level", "log.level", "will be removed in v3")
+	c.Deprecate("debug", "app.debug", "")
+
+	is.NoErr(c.LoadStrings(JSON, `{"name": "demo"}`))
+	is.Empty(warns)
+
+	is.NoErr(c.LoadStrings(JSON, `{"log_level": "info"}`))
+	is.Eq([]string{"log_level -> log.level: will be removed in v3"}, warns)
+	is.Eq("info", c.String("log.level"))
+	is.Eq("info", c.String("log_level"))
+
+	// only warn once per key
+	is.NoErr(c.LoadData(map[string]any{"log_level": "debug"}))
+	is.Len(warns, 1)
+	is.Eq("debug", c.String("log.level"))
+
+	// found on set data
+	c.SetData(map[string]any{"debug": true})
+	is.Len(warns, 2)
+	is.Eq("debug -> app.debug: ", warns[1])
+	is.True(c.Bool("app.debug"))
+
+	// found in the loaded data on deprecate
+	c.Set("old_name", "demo", false)
+	c.Deprecate("old_name", "name", "")
+	is.Len(warns, 3)
+	is.Eq("demo", c.String("name"))
+}
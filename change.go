@@ -0,0 +1,139 @@
+package config
+
+import "fmt"
+
+// ChangeEvent the event data of a key path changed. see Config.OnChange()
+type ChangeEvent struct {
+	// DiffItem the changed key path, type, old value and new value.
+	DiffItem
+	// Source of the change, is the hook event name. eg: OnSetValue, OnDeleteValue, OnLoadData, OnReloadData
+	Source string
+}
+
+// ChangeFunc the listener func for Config.OnChange()
+type ChangeFunc func(ev ChangeEvent)
+
+type changeListener struct {
+	// the pattern keys, empty is match all keys
+	keys []string
+	fn   ChangeFunc
+}
+
+// check the listener matches the changed keys. the pattern key "*" matches any key,
+// and the parent or child paths of the pattern are matched too.
+func (l *changeListener) match(keys []string) bool {
+	for i := 0; i < len(l.keys) && i < len(keys); i++ {
+		if l.keys[i] != "*" && l.keys[i] != keys[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// OnChange add a listener for the key path changed. see Config.OnChange()
+func OnChange(pattern string, fn ChangeFunc) { dc.OnChange(pattern, fn) }
+
+// OnChangeE add a listener for the key path changed. see Config.OnChangeE()
+func OnChangeE(pattern string, fn ChangeFunc) error { return dc.OnChangeE(pattern, fn) }
+
+// OnChange add a listener for the key path changed. the pattern supports the wildcard "*", empty is match all keys.
+//
+// The listener is fired on Set, Delete, load and reload data, one event for each changed key path.
+// The changed key paths are computed by diffing the data before and after, see Diff().
+// NOTE: the added or removed map is reported as a whole, not for each child key.
+// The listener is fired for the paths under the pattern, and the parent path of the pattern
+// is changed as a whole. eg: pattern "db.*" is fired on "db.host" or "db" changed.
+//
+// For the scope view, the pattern is relative to the scope key, the ChangeEvent.Path is the full key path.
+// will panic on the pattern is invalid, see OnChangeE().
+//
+// Usage:
+//
+//	c.OnChange("db.*", func(ev config.ChangeEvent) {
+//		fmt.Println(ev.Source, ev.Path, ev.Old, "->", ev.New)
+//	})
+func (c *Config) OnChange(pattern string, fn ChangeFunc) {
+	if err := c.OnChangeE(pattern, fn); err != nil {
+		panic(err)
+	}
+}
+
+// OnChangeE add a listener for the key path changed, returns error on the pattern is invalid. see Config.OnChange()
+func (c *Config) OnChangeE(pattern string, fn ChangeFunc) error {
+	root := c
+	if c.root != nil {
+		root = c.root
+	}
+
+	var keys []string
+	if pattern != "" {
+		var err error
+		if keys, err = c.parseKeys(pattern); err != nil {
+			return fmt.Errorf("config: invalid change pattern '%s': %w", pattern, err)
+		}
+	}
+
+	keys = append(append([]string{}, c.prefix...), keys...)
+	keys, _ = root.resolveAliasKeys(keys)
+
+	root.changeLock.Lock()
+	root.listeners = append(root.listeners, &changeListener{keys: keys, fn: fn})
+	root.changeLock.Unlock()
+	return nil
+}
+
+func (c *Config) hasChangeListeners() bool {
+	c.changeLock.RLock()
+	defer c.changeLock.RUnlock()
+	return len(c.listeners) > 0
+}
+
+// collect the change events by diff the old and new value of the key path. keys is empty on diff the whole data.
+//
+// inOld, inNew mark the value exists. returns nil on source is empty or no listeners.
+func (c *Config) collectChanges(source string, keys []string, old any, inOld bool, new any, inNew bool) []ChangeEvent {
+	if source == "" || !c.hasChangeListeners() {
+		return nil
+	}
+
+	d := &differ{sep: c.opts.Delimiter}
+	d.diffKey(keys, old, inOld, new, inNew)
+
+	events := make([]ChangeEvent, len(d.items))
+	for i, item := range d.items {
+		// copy the values, the listener should not change the config data
+		item.Old, item.New = deepCopy(item.Old), deepCopy(item.New)
+		events[i] = ChangeEvent{DiffItem: item, Source: source}
+	}
+	return events
+}
+
+// fire the change listeners for the events. must call it without the lock, see Config.flushEvents()
+func (c *Config) fireChangeEvents(events []ChangeEvent) {
+	if len(events) == 0 {
+		return
+	}
+
+	c.changeLock.RLock()
+	listeners := c.listeners
+	c.changeLock.RUnlock()
+
+	for _, ev := range events {
+		for _, l := range listeners {
+			if l.match(ev.keys) {
+				c.callChangeListener(l, ev)
+			}
+		}
+	}
+}
+
+// call the change listener and recover the panic
+func (c *Config) callChangeListener(l *changeListener, ev ChangeEvent) {
+	defer func() {
+		if r := recover(); r != nil {
+			c.addErrorf("config: the change listener of the key %q panic: %v", ev.Path, r)
+		}
+	}()
+
+	l.fn(ev)
+}
@@ -0,0 +1,150 @@
+package config
+
+import (
+	"os"
+	"testing"
+
+	"github.com/gookit/goutil/testutil/assert"
+)
+
+func TestConfig_OnChange(t *testing.T) {
+	is := assert.New(t)
+	c := New("test")
+
+	var events []ChangeEvent
+	c.OnChange("db.*", func(ev ChangeEvent) {
+		// can read the config in the listener
+		is.Eq(ev.New, c.Get(ev.Path))
+		events = append(events, ev)
+	})
+	var all []string
+	c.OnChange("", func(ev ChangeEvent) {
+		all = append(all, ev.Source+":"+ev.Path)
+	})
+
+	// load
+	err := c.LoadStrings(JSON, `{"name": "app", "db": {"host": "localhost", "port": 3306}}`)
+	is.NoErr(err)
+	is.Len(events, 1)
+	is.Eq([]string{"load.data:db", "load.data:name"}, all)
+	// the new map is added as a whole
+	is.Eq(ChangeEvent{
+		DiffItem: DiffItem{
+			Path: "db",
+			Type: ChangeAdded,
+			New:  map[string]any{"host": "localhost", "port": float64(3306)},
+			keys: []string{"db"},
+		},
+		Source: OnLoadData,
+	}, events[0])
+
+	// merge load, only the changed keys
+	events, all = nil, nil
+	err = c.LoadStrings(JSON, `{"db": {"host": "db.local", "port": 3306}}`)
+	is.NoErr(err)
+	is.Len(events, 1)
+	is.Eq("db.host", events[0].Path)
+	is.Eq(ChangeChanged, events[0].Type)
+	is.Eq("localhost", events[0].Old)
+	is.Eq("db.local", events[0].New)
+
+	// set
+	events, all = nil, nil
+	is.NoErr(c.Set("db.user", "root"))
+	is.NoErr(c.Set("name", "app2"))
+	is.NoErr(c.Set("db.port", 3306))
+	is.Len(events, 1)
+	is.Eq("db.user", events[0].Path)
+	is.Eq(OnSetValue, events[0].Source)
+	is.Eq([]string{"set.value:db.user", "set.value:name"}, all)
+
+	// set the parent path as a whole
+	events = nil
+	is.NoErr(c.Set("db", map[string]any{"host": "db.local", "port": 3307}))
+	is.Len(events, 2)
+	is.Eq("db.port", events[0].Path)
+	is.Eq(ChangeRemoved, events[1].Type)
+	is.Eq("root", events[1].Old)
+
+	// delete
+	events = nil
+	is.NoErr(c.Delete("db.port"))
+	is.Len(events, 1)
+	is.Eq(ChangeEvent{
+		DiffItem: DiffItem{Path: "db.port", Type: ChangeRemoved, Old: 3307, keys: []string{"db", "port"}},
+		Source:   OnDeleteValue,
+	}, events[0])
+
+	// set data
+	events, all = nil, nil
+	c.SetData(map[string]any{"db": map[string]any{"host": "db.local"}})
+	is.Len(events, 0)
+	is.Eq([]string{"set.data:name"}, all)
+
+	// clear data
+	events = nil
+	c.ClearData()
+	is.Len(events, 1)
+	is.Eq(OnCleanData, events[0].Source)
+	is.Eq("db", events[0].Path)
+}
+
+func TestConfig_OnChange_scope(t *testing.T) {
+	is := assert.New(t)
+	c := New("test")
+	err := c.LoadStrings(JSON, `{"db": {"host": "localhost", "opts": {"timeout": "3s"}}, "name": "app"}`)
+	is.NoErr(err)
+
+	var paths []string
+	db := c.Scope("db")
+	db.OnChange("opts.*", func(ev ChangeEvent) {
+		paths = append(paths, ev.Path)
+	})
+
+	is.NoErr(c.Set("db.opts.timeout", "5s"))
+	is.NoErr(db.Set("opts.retry", 3))
+	is.NoErr(db.Set("host", "db.local"))
+	is.NoErr(c.Set("name", "app2"))
+	is.Eq([]string{"db.opts.timeout", "db.opts.retry"}, paths)
+
+	is.Panics(func() {
+		c.OnChange(`db["host`, func(ev ChangeEvent) {})
+	})
+
+	// returns error
+	err = c.OnChangeE(`db["host`, func(ev ChangeEvent) {})
+	is.ErrSubMsg(err, `config: invalid change pattern 'db["host'`)
+	is.ErrIs(db.OnChangeE(".", func(ev ChangeEvent) {}), ErrKeyIsEmpty)
+	is.NoErr(c.OnChangeE("", func(ev ChangeEvent) {}))
+}
+
+func TestConfig_OnChange_reload(t *testing.T) {
+	is := assert.New(t)
+	file := t.TempDir() + "/app.json"
+	is.NoErr(os.WriteFile(file, []byte(`{"name": "app", "db": {"host": "localhost", "port": 3306}}`), 0644))
+
+	c := New("test")
+	is.NoErr(c.LoadFiles(file))
+
+	var events []ChangeEvent
+	c.OnChange("", func(ev ChangeEvent) {
+		events = append(events, ev)
+	})
+
+	// no changes
+	is.NoErr(c.ReloadFiles())
+	is.Len(events, 0)
+
+	is.NoErr(os.WriteFile(file, []byte(`{"db": {"host": "db.local", "port": 3306, "user": "root"}}`), 0644))
+	is.NoErr(c.ReloadFiles())
+	is.Len(events, 3)
+	is.Eq("db.host", events[0].Path)
+	is.Eq(ChangeChanged, events[0].Type)
+	is.Eq("db.user", events[1].Path)
+	is.Eq(ChangeAdded, events[1].Type)
+	is.Eq("name", events[2].Path)
+	is.Eq(ChangeRemoved, events[2].Type)
+	for _, ev := range events {
+		is.Eq(OnReloadData, ev.Source)
+	}
+}
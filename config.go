@@ -40,7 +40,9 @@ package config
 
 import (
 	"fmt"
+	"maps"
 	"sync"
+	"sync/atomic"
 )
 
 // There are supported config format
@@ -77,34 +79,66 @@ var dc = New("default")
 type Config struct {
 	// save the latest error, will clear after read.
 	err error
+	// the error may be recorded by the concurrent readers and the async listeners
+	errLock sync.Mutex
 	// config instance name
 	name string
 	lock sync.RWMutex
 
 	// config options
 	opts *Options
-	// all config data
-	data map[string]any
+	// all config data. it is an immutable tree, the writers copy the changed path and publish a new one,
+	// so the readers never block. see getData() and storeData()
+	data atomic.Pointer[map[string]any]
+
+	// the root config of the scope view, it is nil on the root config. see Scope()
+	root *Config
+	// the key path of the scope view in the root config
+	prefix    []string
+	scopePath string
+	// the scope views which have the HookFunc, will fire hooks on the sub data changed.
+	scopes []*Config
+	// the event listeners. see Subscribe()
+	bus eventBus
+	// the events waiting for delivery after the lock is released
+	queue eventQueue
+	// the data version, is increased on each data changed. see Snapshot()
+	version atomic.Uint64
+	// the snapshots kept on reload data. see Options.HistorySize
+	history []*Snapshot
+	// the key change listeners. see OnChange()
+	listeners  []*changeListener
+	changeLock sync.RWMutex
 
 	// loaded config files records
 	loadedUrls  []string
 	loadedFiles []string
 	driverNames []string
 	// driver alias to name map.
-	aliasMap  map[string]string
-	reloading bool
-
-	// TODO Deprecated decoder and encoder, use driver instead
-	// drivers map[string]Driver
+	aliasMap map[string]string
+
+	// added drivers on the instance, it will be used before the global registry.
+	drivers map[string]DriverV2
+	// comments collected by CommentsDecoder drivers
+	comments map[string]string
+	// the comments may be collected by the concurrent loads
+	commentLock sync.Mutex
+	// config key aliases. alias key path => alias, it is immutable like the data. see AliasKey()
+	keyAliases atomic.Pointer[map[string]*keyAlias]
+	// the deprecated keys which have been warned
+	warnedKeys map[string]bool
 
 	// decoders["toml"] = func(blob []byte, v any) (err error){}
 	// decoders["yaml"] = func(blob []byte, v any) (err error){}
 	decoders map[string]Decoder
 	encoders map[string]Encoder
 
-	// cache on got config data
-	intCache map[string]int
-	strCache map[string]string
+	// cache on got config data, they are reset on the data changed. see lockCaches()
+	cacheLock sync.Mutex
+	// the data which the caches are created from
+	cacheData *map[string]any
+	intCache  map[string]int
+	strCache  map[string]string
 	// iArrCache map[string]intArr TODO cache it
 	// iMapCache map[string]intMap
 	sArrCache map[string]strArr
@@ -128,16 +162,49 @@ func NewEmpty(name string, opts ...OptionFn) *Config {
 	c := &Config{
 		name: name,
 		opts: newDefaultOption(),
-		data: make(map[string]any),
 		// don't add any drivers
+		drivers:  map[string]DriverV2{},
 		encoders: map[string]Encoder{},
 		decoders: map[string]Decoder{},
 		aliasMap: make(map[string]string),
 	}
 
+	c.storeData(make(map[string]any))
 	return c.WithOptions(opts...)
 }
 
+// create a new empty config instance, with same options and drivers as current config.
+func (c *Config) newChild(name string) *Config {
+	opts := *c.opts
+	nc := &Config{
+		name: name,
+		opts: &opts,
+		// copy drivers
+		drivers:  make(map[string]DriverV2, len(c.drivers)),
+		encoders: make(map[string]Encoder, len(c.encoders)),
+		decoders: make(map[string]Decoder, len(c.decoders)),
+		aliasMap: make(map[string]string, len(c.aliasMap)),
+	}
+
+	nc.driverNames = append(nc.driverNames, c.driverNames...)
+	for k, d := range c.drivers {
+		nc.drivers[k] = d
+	}
+	for k, fn := range c.encoders {
+		nc.encoders[k] = fn
+	}
+	for k, fn := range c.decoders {
+		nc.decoders[k] = fn
+	}
+	for k, v := range c.aliasMap {
+		nc.aliasMap[k] = v
+	}
+	// the aliases are immutable, can be shared
+	nc.keyAliases.Store(c.keyAliases.Load())
+	nc.storeData(make(map[string]any))
+	return nc
+}
+
 // NewWith create config instance, and you can call some init func
 func NewWith(name string, fn func(c *Config)) *Config {
 	return New(name).With(fn)
@@ -171,39 +238,100 @@ func AddDriver(driver Driver) { dc.AddDriver(driver) }
 
 // AddDriver set a decoder and encoder driver for a format.
 func (c *Config) AddDriver(driver Driver) {
+	c.AddDriverV2(ToDriverV2(driver))
+}
+
+// AddDriverV2 set a DriverV2 for a format.
+//
+// NOTE: the driver only be used by current instance, use RegisterDriver() to share it.
+func AddDriverV2(driver DriverV2) { dc.AddDriverV2(driver) }
+
+// AddDriverV2 set a DriverV2 for a format. will check the optional interfaces of the driver.
+func (c *Config) AddDriverV2(driver DriverV2) {
 	format := driver.Name()
-	if len(driver.Aliases()) > 0 {
-		for _, alias := range driver.Aliases() {
-			c.aliasMap[alias] = format
-		}
+	for _, alias := range driver.Aliases() {
+		c.aliasMap[alias] = format
 	}
 
 	c.driverNames = append(c.driverNames, format)
-	c.decoders[format] = driver.GetDecoder()
-	c.encoders[format] = driver.GetEncoder()
+	c.drivers[format] = driver
+	c.decoders[format] = driver.Decode
+	c.encoders[format] = driver.Encode
 }
 
-// HasDecoder has decoder
+// HasDecoder has decoder. will check the global registry
 func (c *Config) HasDecoder(format string) bool {
-	format = c.resolveFormat(format)
-	_, ok := c.decoders[format]
-	return ok
+	return c.getDecoder(c.resolveFormat(format)) != nil
 }
 
-// HasEncoder has encoder
+// HasEncoder has encoder. will check the global registry
 func (c *Config) HasEncoder(format string) bool {
-	format = c.resolveFormat(format)
-	_, ok := c.encoders[format]
-	return ok
+	return c.getEncoder(c.resolveFormat(format)) != nil
 }
 
 // DelDriver delete driver of the format
 func (c *Config) DelDriver(format string) {
 	format = c.resolveFormat(format)
+	delete(c.drivers, format)
 	delete(c.decoders, format)
 	delete(c.encoders, format)
 }
 
+// get driver by resolved format name. will find from the global registry.
+func (c *Config) getDriver(format string) (DriverV2, bool) {
+	if d, ok := c.drivers[format]; ok {
+		return d, true
+	}
+
+	// has custom decoder or encoder on the instance.
+	if c.decoders[format] != nil || c.encoders[format] != nil {
+		return nil, false
+	}
+	return LookupDriver(format)
+}
+
+func (c *Config) getDecoder(format string) Decoder {
+	if dec, ok := c.decoders[format]; ok {
+		return dec
+	}
+
+	if d, ok := LookupDriver(format); ok {
+		return d.Decode
+	}
+	return nil
+}
+
+func (c *Config) getEncoder(format string) Encoder {
+	if enc, ok := c.encoders[format]; ok {
+		return enc
+	}
+
+	if d, ok := LookupDriver(format); ok {
+		return d.Encode
+	}
+	return nil
+}
+
+// detect format by content, use the ContentSniffer drivers.
+func (c *Config) sniffFormat(blob []byte) string {
+	for _, name := range c.driverNames {
+		if d, ok := c.drivers[name]; ok {
+			if sn, ok := d.(ContentSniffer); ok && sn.Sniff(blob) {
+				return name
+			}
+		}
+	}
+
+	for _, name := range RegisteredDrivers() {
+		if d, ok := c.getDriver(name); ok {
+			if sn, ok := d.(ContentSniffer); ok && sn.Sniff(blob) {
+				return name
+			}
+		}
+	}
+	return ""
+}
+
 /*************************************************************
  * helper methods
  *************************************************************/
@@ -226,8 +354,18 @@ func (c *Config) AddAlias(format, alias string) {
 // AliasMap get alias map
 func (c *Config) AliasMap() map[string]string { return c.aliasMap }
 
+// Comments get a copy of the comments collected from loaded contents. only for the CommentsDecoder drivers
+func (c *Config) Comments() map[string]string {
+	c.commentLock.Lock()
+	defer c.commentLock.Unlock()
+	return maps.Clone(c.comments)
+}
+
 // Error get last error, will clear after read.
 func (c *Config) Error() error {
+	c.errLock.Lock()
+	defer c.errLock.Unlock()
+
 	err := c.err
 	c.err = nil
 	return err
@@ -235,7 +373,7 @@ func (c *Config) Error() error {
 
 // IsEmpty of the config
 func (c *Config) IsEmpty() bool {
-	return len(c.data) == 0
+	return len(c.rawData()) == 0
 }
 
 // LoadedUrls get loaded urls list
@@ -265,40 +403,120 @@ func (c *Config) ClearAll() {
 
 // ClearData clear data
 func (c *Config) ClearData() {
-	c.fireHook(OnCleanData)
+	if c.root != nil {
+		c.addError(c.root.set(c.scopePath, make(map[string]any), true, OnCleanData))
+		return
+	}
 
-	c.data = make(map[string]any)
+	c.lock.Lock()
+	old, data := c.getData(), make(map[string]any)
+	c.storeData(data)
+	c.commentLock.Lock()
+	c.comments = nil
+	c.commentLock.Unlock()
 	c.loadedUrls = []string{}
 	c.loadedFiles = []string{}
+	c.queueEvent(OnCleanData, nil, c.collectChanges(OnCleanData, nil, old, true, data, true))
+	c.lock.Unlock()
+
+	c.flushEvents()
 }
 
 // ClearCaches clear caches
 func (c *Config) ClearCaches() {
 	if c.opts.EnableCache {
-		c.intCache = nil
-		c.strCache = nil
-		c.sMapCache = nil
-		c.sArrCache = nil
+		c.cacheLock.Lock()
+		c.resetCaches(nil)
+		c.cacheLock.Unlock()
+	}
+}
+
+func (c *Config) resetCaches(data *map[string]any) {
+	c.cacheData = data
+	c.intCache = nil
+	c.strCache = nil
+	c.sMapCache = nil
+	c.sArrCache = nil
+}
+
+// lock the caches, and check the caches can be used for the data which is read by the getter.
+// the caches will be reset on the data changed, returns false if the data is not the current data.
+//
+// NOTE: must call c.cacheLock.Unlock() after use the caches.
+func (c *Config) lockCaches(data *map[string]any) bool {
+	c.cacheLock.Lock()
+	if c.cacheData == data {
+		return true
 	}
+
+	// the data is changed after read, don't use the caches.
+	if data != c.dataPtr() {
+		return false
+	}
+	c.resetCaches(data)
+	return true
+}
+
+// get the current data. it is immutable, should not change it.
+func (c *Config) getData() map[string]any {
+	if p := c.data.Load(); p != nil {
+		return *p
+	}
+	return nil
+}
+
+// publish the new data, the readers will see it atomically.
+// must call it with the write lock, and the data should not be changed after.
+func (c *Config) storeData(data map[string]any) { c.data.Store(&data) }
+
+// get the pointer of the current data, on the scope view is the data of the root config.
+func (c *Config) dataPtr() *map[string]any {
+	if c.root != nil {
+		return c.root.data.Load()
+	}
+	return c.data.Load()
 }
 
 /*************************************************************
  * helper methods
  *************************************************************/
 
-// fire hook
-func (c *Config) fireHook(name string) {
-	if c.opts.HookFunc != nil {
-		c.opts.HookFunc(name, c)
+// fire hook, must call it without the lock.
+func (c *Config) fireHook(name string) { c.fireEvent(name, nil, nil) }
+
+// queue and deliver the event, must call it without the lock.
+func (c *Config) fireEvent(name string, keys []string, changes []ChangeEvent) {
+	c.queueEvent(name, keys, changes)
+	c.flushEvents()
+}
+
+// fire hook on the config and the scope views of the changed keys. keys is empty on the whole data changed.
+func (c *Config) fireChange(name string, keys []string) {
+	if name == "" {
+		return
+	}
+
+	c.publish(name)
+
+	c.lock.RLock()
+	scopes := c.scopes
+	c.lock.RUnlock()
+
+	for _, s := range scopes {
+		if len(keys) == 0 || hasKeysPrefix(keys, s.prefix) || hasKeysPrefix(s.prefix, keys) {
+			s.publish(name)
+		}
 	}
 }
 
 // record error
 func (c *Config) addError(err error) {
+	c.errLock.Lock()
 	c.err = err
+	c.errLock.Unlock()
 }
 
 // format and record error
 func (c *Config) addErrorf(format string, a ...any) {
-	c.err = fmt.Errorf(format, a...)
+	c.addError(fmt.Errorf(format, a...))
 }
@@ -2,8 +2,15 @@ package config
 
 import (
 	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 
 	"github.com/gookit/goutil/maputil"
@@ -154,6 +161,164 @@ func BenchmarkGet(b *testing.B) {
 	}
 }
 
+// the baseline of the read-heavy benchmarks: the data is guarded by a RWMutex and changed in place.
+type rwMutexData struct {
+	lock sync.RWMutex
+	data map[string]any
+}
+
+func (d *rwMutexData) get(key string) any {
+	d.lock.RLock()
+	defer d.lock.RUnlock()
+
+	keys, _ := parsePath(key, defaultDelimiter)
+	val, _ := findByKeys(d.data, keys, 0, defaultDelimiter)
+	return val
+}
+
+func (d *rwMutexData) set(key string, val any) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	keys, _ := parsePathKeys(key, defaultDelimiter)
+	_, _ = setByKeys(d.data, keys, 0, val, defaultDelimiter)
+}
+
+// the copy-on-write data like the Config: the readers load the immutable data, the writers copy the changed path.
+type cowData struct {
+	lock sync.Mutex
+	data atomic.Pointer[map[string]any]
+}
+
+func (d *cowData) get(key string) any {
+	keys, _ := parsePath(key, defaultDelimiter)
+	val, _ := findByKeys(*d.data.Load(), keys, 0, defaultDelimiter)
+	return val
+}
+
+func (d *cowData) set(key string, val any) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	keys, _ := parsePathKeys(key, defaultDelimiter)
+	data := clonePath(*d.data.Load(), keys, 0).(map[string]any)
+	_, _ = setByKeys(data, keys, 0, val, defaultDelimiter)
+	d.data.Store(&data)
+}
+
+// run the read and write concurrently, there is one write for every n reads. no write on n is 0.
+func benchReadHeavy(b *testing.B, n int, read func(), write func(i int)) {
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		var i int
+		for pb.Next() {
+			if i++; n > 0 && i%n == 0 {
+				write(i)
+			} else {
+				read()
+			}
+		}
+	})
+}
+
+func BenchmarkConcurrentRead(b *testing.B) {
+	for _, n := range []int{0, 100, 10} {
+		b.Run(fmt.Sprintf("rwmutex-write-every-%d", n), func(b *testing.B) {
+			c := New("bench")
+			if err := c.LoadStrings(JSON, jsonStr); err != nil {
+				b.Fatal(err)
+			}
+
+			d := &rwMutexData{data: deepCopy(c.Data()).(map[string]any)}
+			benchReadHeavy(b, n, func() {
+				d.get("map1.key")
+			}, func(i int) {
+				d.set("map1.key2", i)
+			})
+		})
+
+		b.Run(fmt.Sprintf("cow-write-every-%d", n), func(b *testing.B) {
+			c := New("bench")
+			if err := c.LoadStrings(JSON, jsonStr); err != nil {
+				b.Fatal(err)
+			}
+
+			d, data := &cowData{}, c.Data()
+			d.data.Store(&data)
+			benchReadHeavy(b, n, func() {
+				d.get("map1.key")
+			}, func(i int) {
+				d.set("map1.key2", i)
+			})
+		})
+
+		// the full Config.Get() and Config.Set(), includes the key format and alias resolving.
+		b.Run(fmt.Sprintf("config-write-every-%d", n), func(b *testing.B) {
+			c := New("bench")
+			if err := c.LoadStrings(JSON, jsonStr); err != nil {
+				b.Fatal(err)
+			}
+
+			benchReadHeavy(b, n, func() {
+				c.Get("map1.key")
+			}, func(i int) {
+				_ = c.Set("map1.key2", i)
+			})
+		})
+	}
+}
+
+func TestConcurrentRead_errors(t *testing.T) {
+	is := assert.New(t)
+	c := New("test")
+	is.NoErr(c.LoadStrings(JSON, `{"name": "app", "debug": "invalid"}`))
+
+	// the readers record the errors concurrently
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				c.Bool("debug")
+				c.Ints("name")
+				_ = c.Error()
+			}
+		}()
+	}
+	wg.Wait()
+
+	c.Bool("debug")
+	is.ErrMsg(c.Error(), "the value 'invalid' cannot be convert to bool")
+}
+
+func TestConfig_Data_copy(t *testing.T) {
+	is := assert.New(t)
+	c := New("test")
+	is.NoErr(c.LoadStrings(JSON, `{"db": {"host": "localhost"}, "app": {"name": "app"}}`))
+
+	// change the returned data will not affect the config
+	data := c.Data()
+	data["name"] = "new"
+	data["db"].(map[string]any)["host"] = "new"
+	is.False(c.Exists("name"))
+	is.Eq("localhost", c.String("db.host"))
+	is.Eq("app", c.Scope("app").Data()["name"])
+
+	// the load copy the changed maps only, the unchanged sub data are shared
+	app, db := c.Sub("app"), c.Sub("db")
+	rawApp := reflect.ValueOf(c.getData()["app"]).Pointer()
+	is.NoErr(c.LoadData(map[string]any{"db": map[string]any{"port": 3306}}))
+	is.Eq(3306, c.Int("db.port"))
+	is.Len(db, 1)
+	is.Eq(rawApp, reflect.ValueOf(c.getData()["app"]).Pointer())
+
+	is.NoErr(c.LoadStrings(JSON, `{"app": {"debug": true}}`))
+	is.True(c.Bool("app.debug"))
+	is.Len(app, 1)
+	is.Eq(3306, c.Int("db.port"))
+}
+
 func TestBasic(t *testing.T) {
 	is := assert.New(t)
 
@@ -183,6 +348,54 @@ func TestGetEnv(t *testing.T) {
 	})
 }
 
+func TestInferValue(t *testing.T) {
+	is := assert.New(t)
+
+	is.Eq("", InferValue("", ","))
+	is.Eq("abc", InferValue("abc", ","))
+	is.Eq(true, InferValue("True", ","))
+	is.Eq(false, InferValue("false", ""))
+	is.Eq(23, InferValue("23", ","))
+	is.Eq(-23, InferValue(" -23 ", ","))
+	is.Eq(0, InferValue("0", ","))
+	is.Eq(0.5, InferValue("0.5", ","))
+	is.Eq("007", InferValue("007", ","))
+	is.Eq("1.2.3", InferValue("1.2.3", ","))
+	is.Eq("NaN", InferValue("NaN", ","))
+	is.Eq("-007", InferValue("-007", ","))
+	is.Eq("+007", InferValue("+007", ","))
+	is.Eq(-0.5, InferValue("-0.5", ","))
+	is.Eq(5, InferValue("+5", ","))
+	is.Eq("0x1F", InferValue("0x1F", ","))
+	for _, s := range []string{"+inf", "-Infinity", "+Inf", "-NaN", "-", "+"} {
+		is.Eq(s, InferValue(s, ","), s)
+	}
+	is.Eq([]any{"a", 2, true}, InferValue("a, 2, true", ","))
+	is.Eq("a, 2", InferValue("a, 2", ""))
+	is.Eq([]any{"a", 2}, InferValue("[a, 2]", ""))
+	is.Eq([]any{}, InferValue("[]", ""))
+}
+
+func TestCollectValue(t *testing.T) {
+	is := assert.New(t)
+
+	data := map[string]any{}
+	is.NoErr(CollectValue(data, []string{"db", "host"}, "localhost", false))
+	is.NoErr(CollectValue(data, []string{"arr"}, 1, true))
+	is.NoErr(CollectValue(data, []string{"arr"}, 2, true))
+	is.Eq(map[string]any{"db": map[string]any{"host": "localhost"}, "arr": []any{1, 2}}, data)
+
+	// conflicts in any order
+	is.ErrMsg(CollectValue(data, []string{"db"}, "val", false), `config: cannot set value for "db", it is a map`)
+	is.ErrMsg(CollectValue(data, []string{"db", "host", "name"}, "val", false), `config: cannot set value for "db.host.name", "db.host" is not a map`)
+	is.ErrMsg(CollectValue(data, []string{"db", "host"}, "val", true), `config: cannot append value for "db.host", it is not a slice`)
+
+	// decode to struct
+	st := struct{ Arr []int }{}
+	is.NoErr(DecodeMapTo(data, &st, "mapstructure"))
+	is.Eq([]int{1, 2}, st.Arr)
+}
+
 func TestSetDecoderEncoder(t *testing.T) {
 	is := assert.New(t)
 
@@ -310,6 +523,167 @@ func TestStdDriver_methods(t *testing.T) {
 	assert.StrContains(t, string(bs), `{"age":245}`)
 }
 
+func TestRegisterDriver(t *testing.T) {
+	is := assert.New(t)
+
+	d := NewDriver("my-json", JSONDecoder, JSONEncoder).WithAlias("mjs")
+	RegisterDriver(d)
+	defer UnregisterDriver("mjs")
+
+	drv, ok := LookupDriver("mjs")
+	is.True(ok)
+	is.Eq("my-json", drv.Name())
+	is.Contains(RegisteredDrivers(), "my-json")
+
+	// will find from the global registry
+	c := NewEmpty("test")
+	is.True(c.HasDecoder("my-json"))
+	is.True(c.HasEncoder("mjs"))
+	is.NoErr(c.LoadStrings("mjs", `{"name": "inhere"}`))
+	is.Eq("inhere", c.String("name"))
+
+	buf := new(bytes.Buffer)
+	_, err := c.DumpTo(buf, "mjs")
+	is.NoErr(err)
+	is.StrContains(buf.String(), `{"name":"inhere"}`)
+
+	// custom decoder on the instance has priority
+	c.SetDecoder("my-json", func(blob []byte, v any) error {
+		return errors.New("custom decoder")
+	})
+	is.ErrMsg(c.LoadStrings("my-json", `{}`), "custom decoder")
+
+	UnregisterDriver("mjs")
+	_, ok = LookupDriver("my-json")
+	is.False(ok)
+	is.False(NewEmpty("test1").HasDecoder("mjs"))
+}
+
+// streamDriver implement StreamDecoder, OptionsEncoder, ContentSniffer
+type streamDriver struct {
+	*StdDriver
+	streamed  int
+	encodeOpt *EncodeOptions
+}
+
+func (d *streamDriver) DecodeReader(r io.Reader, v any) error {
+	d.streamed++
+	return json.NewDecoder(r).Decode(v)
+}
+
+func (d *streamDriver) EncodeWith(v any, opts *EncodeOptions) ([]byte, error) {
+	d.encodeOpt = opts
+	return JSONDriver.EncodeWith(v, opts)
+}
+
+func (d *streamDriver) Sniff(blob []byte) bool {
+	return bytes.HasPrefix(blob, []byte("{\"test\""))
+}
+
+// commentsDriver implement CommentsDecoder
+type commentsDriver struct {
+	*StdDriver
+}
+
+func (d *commentsDriver) DecodeWithComments(blob []byte, v any) (map[string]string, error) {
+	return map[string]string{"name": "the app name"}, d.Decode(blob, v)
+}
+
+// docsDriver implement MultiDocsDecoder, MultiDocsEncoder
+type docsDriver struct {
+	*StdDriver
+}
+
+func (d *docsDriver) DecodeDocs(blob []byte) ([]map[string]any, error) {
+	var docs []map[string]any
+	for _, doc := range strings.Split(string(blob), "\n---\n") {
+		data := make(map[string]any)
+		if err := d.Decode([]byte(doc), &data); err != nil {
+			return nil, err
+		}
+		docs = append(docs, data)
+	}
+	return docs, nil
+}
+
+func (d *docsDriver) EncodeDocs(docs []any) ([]byte, error) {
+	var parts []string
+	for _, doc := range docs {
+		bs, err := d.Encode(doc)
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, string(bs))
+	}
+	return []byte(strings.Join(parts, "\n---\n")), nil
+}
+
+func TestDriver_optionalInterfaces(t *testing.T) {
+	is := assert.New(t)
+
+	d := &streamDriver{StdDriver: NewDriver("test", JSONDecoder, JSONEncoder)}
+	c := NewEmpty("test")
+	c.AddDriver(d)
+
+	// StreamDecoder
+	fpath := t.TempDir() + "/data.test"
+	is.NoErr(os.WriteFile(fpath, []byte(`{"name": "app"}`), 0644))
+	is.NoErr(c.LoadFiles(fpath))
+	is.Eq(1, d.streamed)
+	is.Eq("app", c.String("name"))
+
+	// detect format by ContentSniffer
+	fpath = t.TempDir() + "/noext"
+	is.NoErr(os.WriteFile(fpath, []byte(`{"test": true}`), 0644))
+	is.NoErr(c.LoadFiles(fpath))
+	is.True(c.Bool("test"))
+
+	err := c.LoadSources("", []byte(`name: app`))
+	is.ErrMsg(err, "cannot detect the format of the config content")
+
+	// JSON driver can also detect JSON content
+	c1 := New("json")
+	is.NoErr(c1.LoadStrings("", ` // comments
+{"age": 23}`))
+	is.Eq(23, c1.Int("age"))
+
+	// CommentsDecoder
+	c = NewEmpty("test")
+	c.AddDriver(&commentsDriver{StdDriver: NewDriver("test", JSONDecoder, JSONEncoder)})
+	is.NoErr(c.LoadStrings("test", `{"name": "app"}`))
+	is.Eq("the app name", c.Comments()["name"])
+	// returns a copy
+	c.Comments()["name"] = "changed"
+	is.Eq("the app name", c.Comments()["name"])
+
+	// concurrent loads and reads
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := c.LoadStrings("test", `{"name": "app"}`); err != nil {
+				t.Error(err)
+			}
+			_ = c.Comments()
+		}()
+	}
+	wg.Wait()
+	is.Eq("the app name", c.Comments()["name"])
+
+	c.ClearData()
+	is.Nil(c.Comments())
+
+	// MultiDocsDecoder
+	c = NewEmpty("test")
+	c.AddDriver(&docsDriver{StdDriver: NewDriver("test", JSONDecoder, JSONEncoder)})
+	is.NoErr(c.LoadStrings("test", `{"name": "app", "age": 2}
+---
+{"name": "app2"}`))
+	is.Eq("app2", c.String("name"))
+	is.Eq(2, c.Int("age"))
+}
+
 func TestOptions(t *testing.T) {
 	is := assert.New(t)
 
@@ -416,6 +790,20 @@ func TestEnableCache(t *testing.T) {
 	sMap = c.StringMap("map1")
 	is.Eq("val1", sMap["key1"])
 
+	// the caches are reset on the data changed
+	is.NoErr(c.Set("name", "app2"))
+	is.Eq("app2", c.String("name"))
+	is.NoErr(c.Set("arr1", []string{"a", "b"}))
+	is.Eq([]string{"a", "b"}, c.Strings("arr1"))
+	is.NoErr(c.Set("map1.key1", "new"))
+	is.Eq("new", c.StringMap("map1")["key1"])
+
+	// the scope view
+	m1 := c.Scope("map1")
+	is.Eq("new", m1.String("key1"))
+	is.NoErr(c.Set("map1.key1", "new2"))
+	is.Eq("new2", m1.String("key1"))
+
 	c.ClearAll()
 }
 
@@ -462,6 +850,25 @@ func TestSaveFileOnSet(t *testing.T) {
 	is.Eq("new-value", c.Get("new-key"))
 }
 
+func TestSaveFileOnSet_delete(t *testing.T) {
+	is := assert.New(t)
+	file := t.TempDir() + "/config.json"
+	c := New("test", SaveFileOnSet(file, JSON))
+	is.NoErr(c.LoadStrings(JSON, `{"name": "app", "debug": true}`))
+
+	is.NoErr(c.Set("name", "app2"))
+	bs, err := os.ReadFile(file)
+	is.NoErr(err)
+	is.Eq(`{"debug":true,"name":"app2"}`, strings.TrimSpace(string(bs)))
+
+	// save on delete
+	is.NoErr(c.Delete("debug"))
+	bs, err = os.ReadFile(file)
+	is.NoErr(err)
+	is.Eq(`{"name":"app2"}`, strings.TrimSpace(string(bs)))
+	is.NoErr(c.Error())
+}
+
 func TestMapStringStringParseEnv(t *testing.T) {
 	is := assert.New(t)
 	c := New("test")
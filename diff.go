@@ -0,0 +1,297 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ChangeType the change type of the DiffItem
+type ChangeType string
+
+// There are change types of the DiffItem
+const (
+	ChangeAdded   ChangeType = "added"
+	ChangeRemoved ChangeType = "removed"
+	ChangeChanged ChangeType = "changed"
+)
+
+// DiffItem a changed key path of the Diff()
+type DiffItem struct {
+	// Path the full key path. eg: "db.host", "servers.0.port"
+	Path string     `json:"path"`
+	Type ChangeType `json:"type"`
+	// Old value, is nil on added
+	Old any `json:"old,omitempty"`
+	// New value, is nil on removed
+	New any `json:"new,omitempty"`
+	// the keys of the path, use for JSON Patch
+	keys []string
+}
+
+// DiffResult the result of the Diff()
+type DiffResult struct {
+	// Items the changed items, in the Walk() order.
+	Items []DiffItem
+}
+
+// Diff compare two configs, returns the added, removed and changed key paths with old and new values.
+//
+// The maps are compared by keys recursively, the slices are compared by index if they have the same length,
+// otherwise the slice is changed as a whole. the numbers are compared by value, so 1(int) == 1.0(float64).
+//
+// Usage:
+//
+//	old := config.New("old")
+//	_ = old.LoadFiles("config.json")
+//	newC := config.New("new")
+//	_ = newC.LoadFiles("config.new.json")
+//
+//	dr := config.Diff(old, newC)
+//	fmt.Print(dr.String())
+//	// - db.host: "localhost"
+//	// + db.host: "db.local"
+//	// + db.user: "root"
+func Diff(a, b *Config) *DiffResult {
+	aData := deepCopy(a.rawData())
+	bData := deepCopy(b.rawData())
+
+	return &DiffResult{Items: diffData(aData, bData, a.opts.Delimiter)}
+}
+
+// compare two data, returns the changed items.
+func diffData(a, b any, sep byte) []DiffItem {
+	d := &differ{sep: sep}
+	d.diff(a, b, nil)
+	return d.items
+}
+
+type differ struct {
+	sep   byte
+	items []DiffItem
+}
+
+func (d *differ) add(typ ChangeType, keys []string, old, new any) {
+	d.items = append(d.items, DiffItem{
+		Path: keysToPath(keys, d.sep),
+		Type: typ,
+		Old:  old,
+		New:  new,
+		keys: append([]string(nil), keys...),
+	})
+}
+
+// diff the values of the key path, inA, inB mark the value exists.
+func (d *differ) diffKey(keys []string, a any, inA bool, b any, inB bool) {
+	switch {
+	case inA && inB:
+		d.diff(a, b, keys)
+	case inB:
+		d.add(ChangeAdded, keys, nil, b)
+	case inA:
+		d.add(ChangeRemoved, keys, a, nil)
+	}
+}
+
+func (d *differ) diff(a, b any, keys []string) {
+	av, bv := reflect.ValueOf(a), reflect.ValueOf(b)
+	switch {
+	case av.Kind() == reflect.Map && bv.Kind() == reflect.Map:
+		aMap, bMap := childMap(a), childMap(b)
+		for _, k := range sortedKeys(unionKeys(aMap, bMap)) {
+			aVal, inA := aMap[k]
+			bVal, inB := bMap[k]
+			d.diffKey(append(keys[:len(keys):len(keys)], k), aVal, inA, bVal, inB)
+		}
+	case av.Kind() == reflect.Slice && bv.Kind() == reflect.Slice && av.Len() == bv.Len():
+		for i := 0; i < av.Len(); i++ {
+			sub := append(keys[:len(keys):len(keys)], fmt.Sprint(i))
+			d.diff(av.Index(i).Interface(), bv.Index(i).Interface(), sub)
+		}
+	default:
+		if !valueEqual(a, b) {
+			d.add(ChangeChanged, keys, a, b)
+		}
+	}
+}
+
+// get the children of the map value, the keys are converted to string.
+func childMap(val any) map[string]any {
+	if mp, ok := val.(map[string]any); ok {
+		return mp
+	}
+
+	mp := make(map[string]any)
+	eachChild(val, func(key string, val any) {
+		mp[key] = val
+	})
+	return mp
+}
+
+func unionKeys(a, b map[string]any) map[string]any {
+	keys := make(map[string]any, len(a)+len(b))
+	for k := range a {
+		keys[k] = nil
+	}
+	for k := range b {
+		keys[k] = nil
+	}
+	return keys
+}
+
+// check the two values are equal. the numbers are compared by value.
+func valueEqual(a, b any) bool {
+	if af, ok := toFloat64(a); ok {
+		if bf, ok := toFloat64(b); ok {
+			return af == bf
+		}
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func toFloat64(val any) (float64, bool) {
+	rv := reflect.ValueOf(val)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	}
+	return 0, false
+}
+
+// IsEmpty check there are no changes
+func (r *DiffResult) IsEmpty() bool { return len(r.Items) == 0 }
+
+// Added get the added items
+func (r *DiffResult) Added() []DiffItem { return r.filter(ChangeAdded) }
+
+// Removed get the removed items
+func (r *DiffResult) Removed() []DiffItem { return r.filter(ChangeRemoved) }
+
+// Changed get the changed items
+func (r *DiffResult) Changed() []DiffItem { return r.filter(ChangeChanged) }
+
+func (r *DiffResult) filter(typ ChangeType) []DiffItem {
+	var items []DiffItem
+	for _, item := range r.Items {
+		if item.Type == typ {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// String render the changes as unified text. the values are encoded by JSON.
+//
+// eg:
+//
+//	fmt.Print(dr.String())
+//	// - db.host: "localhost"
+//	// + db.host: "db.local"
+//	// + db.user: "root"
+func (r *DiffResult) String() string {
+	var buf bytes.Buffer
+	for _, item := range r.Items {
+		if item.Type != ChangeAdded {
+			buf.WriteString("- " + item.Path + ": " + formatDiffValue(item.Old) + "\n")
+		}
+		if item.Type != ChangeRemoved {
+			buf.WriteString("+ " + item.Path + ": " + formatDiffValue(item.New) + "\n")
+		}
+	}
+	return buf.String()
+}
+
+// JSON render the changes as JSON list. eg: [{"path": "db.host", "type": "changed", "old": "localhost", "new": "db.local"}]
+func (r *DiffResult) JSON() ([]byte, error) {
+	items := make([]DiffItem, len(r.Items))
+	for i, item := range r.Items {
+		item.Old, item.New = toJSONValue(item.Old), toJSONValue(item.New)
+		items[i] = item
+	}
+	return json.Marshal(items)
+}
+
+// jsonPatchOp an operation of the JSON Patch(RFC 6902)
+type jsonPatchOp struct {
+	Op   string `json:"op"`
+	Path string `json:"path"`
+	// the encoded value, is empty on "remove". the null value is kept for "add" and "replace".
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// JSONPatch render the changes as JSON Patch(RFC 6902), apply it to the old data will get the new data.
+//
+// eg: [{"op": "replace", "path": "/db/host", "value": "db.local"}, {"op": "add", "path": "/db/user", "value": "root"}]
+func (r *DiffResult) JSONPatch() ([]byte, error) {
+	ops := make([]jsonPatchOp, len(r.Items))
+	for i, item := range r.Items {
+		op := jsonPatchOp{Path: jsonPointer(item.keys)}
+		switch item.Type {
+		case ChangeAdded:
+			op.Op = "add"
+		case ChangeRemoved:
+			op.Op = "remove"
+		default:
+			op.Op = "replace"
+		}
+
+		if op.Op != "remove" {
+			bs, err := json.Marshal(toJSONValue(item.New))
+			if err != nil {
+				return nil, err
+			}
+			op.Value = bs
+		}
+		ops[i] = op
+	}
+	return json.Marshal(ops)
+}
+
+// build the JSON Pointer(RFC 6901) by keys. eg: ["db", "host"] -> "/db/host"
+func jsonPointer(keys []string) string {
+	var sb strings.Builder
+	escaper := strings.NewReplacer("~", "~0", "/", "~1")
+	for _, key := range keys {
+		sb.WriteByte('/')
+		sb.WriteString(escaper.Replace(key))
+	}
+	return sb.String()
+}
+
+func formatDiffValue(val any) string {
+	bs, err := json.Marshal(toJSONValue(val))
+	if err != nil {
+		return fmt.Sprintf("%v", val)
+	}
+	return string(bs)
+}
+
+// convert the value for JSON encode. eg: map[any]any to map[string]any
+func toJSONValue(val any) any {
+	rv := reflect.ValueOf(val)
+	switch rv.Kind() {
+	case reflect.Map:
+		mp := make(map[string]any, rv.Len())
+		eachChild(val, func(key string, v any) {
+			mp[key] = toJSONValue(v)
+		})
+		return mp
+	case reflect.Slice:
+		if rv.IsNil() {
+			return val
+		}
+
+		list := make([]any, rv.Len())
+		for i := range list {
+			list[i] = toJSONValue(rv.Index(i).Interface())
+		}
+		return list
+	}
+	return val
+}
@@ -0,0 +1,104 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/gookit/goutil/testutil/assert"
+)
+
+func TestDiff(t *testing.T) {
+	is := assert.New(t)
+
+	a := New("old")
+	err := a.LoadStrings(JSON, `{
+		"name": "app",
+		"debug": true,
+		"db": {"host": "localhost", "port": 3306},
+		"tags": ["a", "b"],
+		"servers": [{"port": 80}, {"port": 443}],
+		"my/key": "v1"
+	}`)
+	is.NoErr(err)
+
+	b := New("new")
+	err = b.LoadData(map[string]any{
+		"name":    "app",
+		"db":      map[string]any{"host": "db.local", "port": 3306, "user": "root"},
+		"tags":    []string{"a", "b", "c"},
+		"servers": []any{map[string]any{"port": 80}, map[string]any{"port": 8443}},
+		"my/key":  "v2",
+	})
+	is.NoErr(err)
+
+	dr := Diff(a, b)
+	is.False(dr.IsEmpty())
+	is.Len(dr.Items, 6)
+	is.Len(dr.Added(), 1)
+	is.Len(dr.Removed(), 1)
+	is.Len(dr.Changed(), 4)
+
+	is.Eq(DiffItem{Path: "db.user", Type: ChangeAdded, New: "root", keys: []string{"db", "user"}}, dr.Added()[0])
+	is.Eq(DiffItem{Path: "debug", Type: ChangeRemoved, Old: true, keys: []string{"debug"}}, dr.Removed()[0])
+
+	// the numbers are compared by value, the slices with different length are changed as a whole
+	is.Eq(`- db.host: "localhost"
++ db.host: "db.local"
++ db.user: "root"
+- debug: true
+- my/key: "v1"
++ my/key: "v2"
+- servers.1.port: 443
++ servers.1.port: 8443
+- tags: ["a","b"]
++ tags: ["a","b","c"]
+`, dr.String())
+
+	bs, err := dr.JSON()
+	is.NoErr(err)
+	is.Contains(string(bs), `{"path":"db.host","type":"changed","old":"localhost","new":"db.local"}`)
+	is.Contains(string(bs), `{"path":"debug","type":"removed","old":true}`)
+
+	bs, err = dr.JSONPatch()
+	is.NoErr(err)
+	is.Eq(`[{"op":"replace","path":"/db/host","value":"db.local"},`+
+		`{"op":"add","path":"/db/user","value":"root"},`+
+		`{"op":"remove","path":"/debug"},`+
+		`{"op":"replace","path":"/my~1key","value":"v2"},`+
+		`{"op":"replace","path":"/servers/1/port","value":8443},`+
+		`{"op":"replace","path":"/tags","value":["a","b","c"]}]`, string(bs))
+
+	// the null value is kept
+	a, b = New("old"), New("new")
+	is.NoErr(a.LoadStrings(JSON, `{"name": "app", "debug": true}`))
+	is.NoErr(b.LoadStrings(JSON, `{"name": null, "debug": true, "opts": null}`))
+	bs, err = Diff(a, b).JSONPatch()
+	is.NoErr(err)
+	is.Eq(`[{"op":"replace","path":"/name","value":null},{"op":"add","path":"/opts","value":null}]`, string(bs))
+
+	// no changes
+	dr = Diff(a, a)
+	is.True(dr.IsEmpty())
+	is.Eq("", dr.String())
+	bs, err = dr.JSONPatch()
+	is.NoErr(err)
+	is.Eq("[]", string(bs))
+}
+
+func TestDiff_mapTypes(t *testing.T) {
+	is := assert.New(t)
+
+	a := New("old")
+	is.NoErr(a.Set("db", map[any]any{"host": "localhost", "port": 3306}, false))
+	b := New("new")
+	is.NoErr(b.Set("db", map[string]any{"host": "db.local", "port": 3306}, false))
+
+	dr := Diff(a, b)
+	is.Len(dr.Items, 1)
+	is.Eq("db.host", dr.Items[0].Path)
+
+	// the value type changed
+	is.NoErr(b.Set("db", "localhost:3306"))
+	dr = Diff(a, b)
+	is.Len(dr.Items, 1)
+	is.Eq("- db: {\"host\":\"localhost\",\"port\":3306}\n+ db: \"localhost:3306\"\n", dr.String())
+}
@@ -1,7 +1,11 @@
 package config
 
 import (
+	"bytes"
 	"encoding/json"
+	"io"
+	"sort"
+	"sync"
 
 	"github.com/gookit/goutil/jsonutil"
 )
@@ -16,6 +20,14 @@ type Driver interface {
 }
 
 // DriverV2 interface.
+//
+// A driver can implement more optional interfaces for support more features:
+//
+//   - StreamDecoder decode data from a reader
+//   - OptionsEncoder encode data with EncodeOptions
+//   - CommentsDecoder collect comments on decode
+//   - MultiDocsDecoder, MultiDocsEncoder support multi documents content. eg: YAML
+//   - ContentSniffer detect format by content
 type DriverV2 interface {
 	Name() string      // driver name, also is format name.
 	Aliases() []string // alias format names, use for resolve format name
@@ -23,6 +35,78 @@ type DriverV2 interface {
 	Encode(v any) (out []byte, err error)
 }
 
+// StreamDecoder decode data from a reader, use for load files and remote data.
+type StreamDecoder interface {
+	DecodeReader(r io.Reader, v any) error
+}
+
+// EncodeOptions for encode data. drivers can ignore the unsupported options.
+//
+// KeyStyle and OmitEmpty are applied to data by Config before encode, see Config.DumpTo
+type EncodeOptions struct {
+	// Indent string for pretty output. default is empty, will use the driver default style.
+	Indent string
+	// SortKeys sort map keys on encode.
+	SortKeys bool
+	// KeyStyle convert map keys to the style on encode. default is KeyStyleKeep
+	KeyStyle KeyStyle
+	// OmitEmpty remove the nil, empty string, empty map and slice values on encode.
+	OmitEmpty bool
+	// LineWidth the preferred max line width, short list and map will be output on one line.
+	LineWidth int
+}
+
+// EncodeOptFn func for set EncodeOptions
+type EncodeOptFn func(eo *EncodeOptions)
+
+// EncodeIndent set indent string for encode data
+func EncodeIndent(indent string) EncodeOptFn {
+	return func(eo *EncodeOptions) { eo.Indent = indent }
+}
+
+// EncodeSortKeys set sort map keys for encode data
+func EncodeSortKeys(eo *EncodeOptions) { eo.SortKeys = true }
+
+// EncodeKeyStyle set the map key style for encode data
+func EncodeKeyStyle(style KeyStyle) EncodeOptFn {
+	return func(eo *EncodeOptions) { eo.KeyStyle = style }
+}
+
+// EncodeOmitEmpty set omit empty values for encode data
+func EncodeOmitEmpty(eo *EncodeOptions) { eo.OmitEmpty = true }
+
+// EncodeLineWidth set the preferred max line width for encode data
+func EncodeLineWidth(width int) EncodeOptFn {
+	return func(eo *EncodeOptions) { eo.LineWidth = width }
+}
+
+// OptionsEncoder encode data with custom options.
+type OptionsEncoder interface {
+	EncodeWith(v any, opts *EncodeOptions) ([]byte, error)
+}
+
+// CommentsDecoder decode data and collect comments from content.
+// The comments map key is defined by the driver.
+type CommentsDecoder interface {
+	DecodeWithComments(blob []byte, v any) (comments map[string]string, err error)
+}
+
+// MultiDocsDecoder decode multi documents content, like YAML with `---` separator.
+type MultiDocsDecoder interface {
+	DecodeDocs(blob []byte) ([]map[string]any, error)
+}
+
+// MultiDocsEncoder encode multi documents to one content.
+type MultiDocsEncoder interface {
+	EncodeDocs(docs []any) ([]byte, error)
+}
+
+// ContentSniffer check the content is the format of driver.
+// use for detect format on the format is not given.
+type ContentSniffer interface {
+	Sniff(blob []byte) bool
+}
+
 // Decoder for decode yml,json,toml format content
 type Decoder func(blob []byte, v any) (err error)
 
@@ -82,6 +166,94 @@ func (d *StdDriver) GetEncoder() Encoder {
 	return d.encoder
 }
 
+// ToDriverV2 convert Driver to DriverV2. if driver has implemented DriverV2, will return itself.
+func ToDriverV2(driver Driver) DriverV2 {
+	if d2, ok := driver.(DriverV2); ok {
+		return d2
+	}
+
+	return &StdDriver{
+		name:    driver.Name(),
+		aliases: driver.Aliases(),
+		decoder: driver.GetDecoder(),
+		encoder: driver.GetEncoder(),
+	}
+}
+
+/*************************************************************
+ * global driver registry
+ *************************************************************/
+
+var (
+	regLock sync.RWMutex
+	// registered drivers, shared by all Config instances.
+	regDrivers = make(map[string]DriverV2)
+	// registered driver alias to name map.
+	regAliases = make(map[string]string)
+)
+
+// RegisterDriver register a driver to the global registry, it is shared by all Config instances.
+//
+// Usage:
+//
+//	func init() {
+//		config.RegisterDriver(yaml.Driver)
+//	}
+func RegisterDriver(driver DriverV2) {
+	regLock.Lock()
+	defer regLock.Unlock()
+
+	format := driver.Name()
+	for _, alias := range driver.Aliases() {
+		regAliases[alias] = format
+	}
+	regDrivers[format] = driver
+}
+
+// UnregisterDriver remove a driver from the global registry
+func UnregisterDriver(format string) {
+	regLock.Lock()
+	defer regLock.Unlock()
+
+	if name, ok := regAliases[format]; ok {
+		format = name
+	}
+
+	delete(regDrivers, format)
+	for alias, name := range regAliases {
+		if name == format {
+			delete(regAliases, alias)
+		}
+	}
+}
+
+// LookupDriver find a driver from the global registry by format name or alias.
+func LookupDriver(format string) (DriverV2, bool) {
+	regLock.RLock()
+	defer regLock.RUnlock()
+
+	if name, ok := regAliases[format]; ok {
+		format = name
+	}
+
+	d, ok := regDrivers[format]
+	return d, ok
+}
+
+// RegisteredDrivers get all registered driver names, sorted by name.
+func RegisteredDrivers() []string {
+	regLock.RLock()
+	defer regLock.RUnlock()
+
+	names := make([]string, 0, len(regDrivers))
+	for name := range regDrivers {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
+
 /*************************************************************
  * JSON driver
  *************************************************************/
@@ -92,20 +264,22 @@ var (
 
 	// JSONMarshalIndent if not empty, will use json.MarshalIndent for encode data.
 	//
-	// Deprecated: please use JSONDriver.MarshalIndent
+	// Deprecated: please use encode options. eg: WithEncodeOptions(EncodeIndent("  ")) or c.DumpTo(w, JSON, EncodeIndent("  "))
 	JSONMarshalIndent string
 )
 
 // JSONDecoder for json decode
 var JSONDecoder Decoder = func(data []byte, v any) (err error) {
-	JSONDriver.ClearComments = JSONAllowComments
-	return JSONDriver.Decode(data, v)
+	if JSONAllowComments {
+		str := jsonutil.StripComments(string(data))
+		return json.Unmarshal([]byte(str), v)
+	}
+	return json.Unmarshal(data, v)
 }
 
 // JSONEncoder for json encode
 var JSONEncoder Encoder = func(v any) (out []byte, err error) {
-	JSONDriver.MarshalIndent = JSONMarshalIndent
-	return JSONDriver.Encode(v)
+	return JSONDriver.EncodeWith(v, &EncodeOptions{Indent: JSONMarshalIndent})
 }
 
 // JSONDriver instance fot json
@@ -160,3 +334,37 @@ func (d *jsonDriver) Encode(v any) (out []byte, err error) {
 func (d *jsonDriver) GetEncoder() Encoder {
 	return d.Encode
 }
+
+// DecodeReader decode JSON data from a reader
+func (d *jsonDriver) DecodeReader(r io.Reader, v any) error {
+	if d.ClearComments {
+		bs, err := io.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		return d.Decode(bs, v)
+	}
+	return json.NewDecoder(r).Decode(v)
+}
+
+// EncodeWith encode data with options. map keys are always sorted by encoding/json
+func (d *jsonDriver) EncodeWith(v any, opts *EncodeOptions) ([]byte, error) {
+	indent := d.MarshalIndent
+	if opts != nil && len(opts.Indent) > 0 {
+		indent = opts.Indent
+	}
+
+	if len(indent) > 0 {
+		return json.MarshalIndent(v, "", indent)
+	}
+	return json.Marshal(v)
+}
+
+// Sniff check the content is JSON object
+func (d *jsonDriver) Sniff(blob []byte) bool {
+	blob = bytes.TrimSpace(blob)
+	if d.ClearComments && len(blob) > 0 && blob[0] == '/' {
+		blob = bytes.TrimSpace([]byte(jsonutil.StripComments(string(blob))))
+	}
+	return len(blob) > 1 && blob[0] == '{'
+}
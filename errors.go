@@ -0,0 +1,74 @@
+package config
+
+import (
+	"fmt"
+)
+
+// NotFoundError the key does not exist in the config. it can be checked by errors.Is(err, ErrNotFound)
+type NotFoundError struct {
+	Key string
+}
+
+// Error string
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("config: the key '%s' does not exist", e.Key)
+}
+
+// Is check the target is ErrNotFound
+func (e *NotFoundError) Is(target error) bool { return target == ErrNotFound }
+
+// ConvertError the value exists, but cannot be converted to the target type.
+type ConvertError struct {
+	Key string
+	// Value the source value
+	Value any
+	// Type the target type name. eg: int, bool
+	Type string
+	// Err the original convert error, may be nil.
+	Err error
+}
+
+// Error string
+func (e *ConvertError) Error() string {
+	msg := fmt.Sprintf("config: cannot convert value %#v(%T) of the key '%s' to %s", e.Value, e.Value, e.Key, e.Type)
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+// Unwrap get the original error
+func (e *ConvertError) Unwrap() error { return e.Err }
+
+// PathError the key path traverses into a non-container value. eg: get "db.host.name" but "db.host" is a string.
+type PathError struct {
+	Key string
+	// Path the path of the non-container value. eg: "db.host"
+	Path string
+	// Value of the Path
+	Value any
+}
+
+// Error string
+func (e *PathError) Error() string {
+	return fmt.Sprintf("config: cannot get value of the key '%s', the value of '%s' is %T, not a map or slice", e.Key, e.Path, e.Value)
+}
+
+// KeyConflictError multi keys are normalized to the same key on Options.KeyMatch is enabled.
+type KeyConflictError struct {
+	// Path the parent path of the keys. it is empty for the top keys.
+	Path string
+	// Key the normalized key
+	Key string
+	// Keys the conflicting source keys
+	Keys []string
+}
+
+// Error string
+func (e *KeyConflictError) Error() string {
+	msg := fmt.Sprintf("config: the keys %q are conflicting, all of them match the key '%s'", e.Keys, e.Key)
+	if e.Path != "" {
+		msg += " in '" + e.Path + "'"
+	}
+	return msg
+}
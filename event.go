@@ -0,0 +1,236 @@
+package config
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ListenOptions the options for subscribe an event. see Config.Subscribe()
+type ListenOptions struct {
+	// Priority the listener with higher priority is called first. default is 0
+	//
+	// The listeners with the same priority are called in the subscribed order,
+	// the Options.HookFunc is called before them.
+	Priority int
+	// Async call the listener in a new goroutine. default is false
+	Async bool
+}
+
+// ListenOptFn the option func for Config.Subscribe()
+type ListenOptFn func(lo *ListenOptions)
+
+// ListenPriority set the priority of the listener
+func ListenPriority(priority int) ListenOptFn {
+	return func(lo *ListenOptions) {
+		lo.Priority = priority
+	}
+}
+
+// ListenAsync set call the listener in a new goroutine
+func ListenAsync(lo *ListenOptions) { lo.Async = true }
+
+// listener a subscriber of the event bus
+type listener struct {
+	ListenOptions
+	id    uint64
+	event string
+	fn    HookFunc
+}
+
+// check the listener matches the event name. "*" matches all events, "set.*" matches the events with prefix "set."
+func (l *listener) match(event string) bool {
+	if l.event == event || l.event == "*" {
+		return true
+	}
+	return strings.HasSuffix(l.event, "*") && strings.HasPrefix(event, l.event[:len(l.event)-1])
+}
+
+// eventBus the event listeners of the config. see Config.Subscribe()
+type eventBus struct {
+	lock   sync.RWMutex
+	lastID uint64
+	items  []*listener
+}
+
+func (b *eventBus) add(l *listener) uint64 {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.lastID++
+	l.id = b.lastID
+	b.items = append(b.items, l)
+	return l.id
+}
+
+func (b *eventBus) remove(id uint64) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	for i, l := range b.items {
+		if l.id == id {
+			b.items = append(b.items[:i:i], b.items[i+1:]...)
+			return
+		}
+	}
+}
+
+func (b *eventBus) isEmpty() bool {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+	return len(b.items) == 0
+}
+
+// get the matched listeners of the event, in the subscribed order.
+func (b *eventBus) matched(event string) []*listener {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+
+	var ls []*listener
+	for _, l := range b.items {
+		if l.match(event) {
+			ls = append(ls, l)
+		}
+	}
+	return ls
+}
+
+// Subscribe an event on the default config. see Config.Subscribe()
+func Subscribe(event string, fn HookFunc, opts ...ListenOptFn) (unsubscribe func()) {
+	return dc.Subscribe(event, fn, opts...)
+}
+
+// Subscribe add a listener for the event, returns the func for remove the listener.
+//
+// The event supports: the event name(eg: OnSetValue), "*" for all events, the prefix wildcard(eg: "set.*").
+// The panic in the listener is recovered, and the error can be got by Config.Error().
+//
+// For the scope view, the listener is only called on the data of the key path changed, same as Options.HookFunc.
+// The view is kept by the root config until all of its listeners are removed.
+//
+// Usage:
+//
+//	unsubscribe := c.Subscribe(config.OnReloadData, func(event string, c *config.Config) {
+//		fmt.Println("config reloaded")
+//	}, config.ListenPriority(10))
+//	defer unsubscribe()
+func (c *Config) Subscribe(event string, fn HookFunc, opts ...ListenOptFn) (unsubscribe func()) {
+	l := &listener{event: event, fn: fn}
+	for _, opt := range opts {
+		opt(&l.ListenOptions)
+	}
+
+	id := c.bus.add(l)
+	if c.root != nil {
+		c.root.addScope(c)
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			c.bus.remove(id)
+			if c.root != nil {
+				c.root.removeScope(c)
+			}
+		})
+	}
+}
+
+// has the Options.HookFunc or listeners for the events.
+func (c *Config) hasListeners() bool {
+	return c.opts.HookFunc != nil || len(c.opts.listeners) > 0 || !c.bus.isEmpty()
+}
+
+// publish the event to the Options.HookFunc and the listeners, in the priority order.
+func (c *Config) publish(event string) {
+	var ls []*listener
+	if c.opts.HookFunc != nil {
+		ls = append(ls, &listener{event: event, fn: c.opts.HookFunc})
+	}
+	for _, l := range c.opts.listeners {
+		if l.match(event) {
+			ls = append(ls, l)
+		}
+	}
+	ls = append(ls, c.bus.matched(event)...)
+
+	sort.SliceStable(ls, func(i, j int) bool {
+		return ls[i].Priority > ls[j].Priority
+	})
+
+	for _, l := range ls {
+		if l.Async {
+			go c.callListener(l, event)
+		} else {
+			c.callListener(l, event)
+		}
+	}
+}
+
+// call the listener and recover the panic
+func (c *Config) callListener(l *listener, event string) {
+	defer func() {
+		if r := recover(); r != nil {
+			c.addErrorf("config: the listener of the event %q panic: %v", event, r)
+		}
+	}()
+
+	l.fn(event, c)
+}
+
+// pendingEvent an event waiting for delivery. see Config.flushEvents()
+type pendingEvent struct {
+	name string
+	// the changed keys, empty is the whole data changed.
+	keys    []string
+	changes []ChangeEvent
+}
+
+// eventQueue the events waiting for delivery, in the order of the data changed.
+type eventQueue struct {
+	lock  sync.Mutex
+	items []pendingEvent
+	// there is a caller delivering the events
+	running bool
+}
+
+// add the event to the queue, and increase the data version.
+// call it with the write lock, so the events are in the order of the data changed.
+func (c *Config) queueEvent(name string, keys []string, changes []ChangeEvent) {
+	if name == "" {
+		return
+	}
+	c.version.Add(1)
+
+	c.queue.lock.Lock()
+	c.queue.items = append(c.queue.items, pendingEvent{name: name, keys: keys, changes: changes})
+	c.queue.lock.Unlock()
+}
+
+// deliver the queued events in order: the hooks and listeners of the event, then the change listeners.
+// must call it without the lock, so the hooks can read and write the config.
+//
+// If the events are delivering, eg: call Set() in a hook or by other goroutine, will return directly,
+// the new events will be delivered by the running caller after the current event.
+// Don't wait for them: the caller in a hook is on the delivering goroutine, wait will be deadlocked.
+func (c *Config) flushEvents() {
+	q := &c.queue
+	q.lock.Lock()
+	if q.running {
+		q.lock.Unlock()
+		return
+	}
+
+	q.running = true
+	for len(q.items) > 0 {
+		ev := q.items[0]
+		q.items = q.items[1:]
+		q.lock.Unlock()
+
+		c.fireChange(ev.name, ev.keys)
+		c.fireChangeEvents(ev.changes)
+		q.lock.Lock()
+	}
+	q.running = false
+	q.lock.Unlock()
+}
@@ -0,0 +1,173 @@
+package config
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gookit/goutil/testutil/assert"
+)
+
+func TestConfig_Subscribe(t *testing.T) {
+	is := assert.New(t)
+
+	var calls []string
+	c := New("test", WithHookFunc(func(event string, c *Config) {
+		calls = append(calls, "hook:"+event)
+	}))
+
+	c.Subscribe(OnSetValue, func(event string, c *Config) {
+		calls = append(calls, "low:"+event)
+	}, ListenPriority(-1))
+	unsub := c.Subscribe("*", func(event string, c *Config) {
+		calls = append(calls, "all:"+event)
+	})
+	c.Subscribe("set.*", func(event string, c *Config) {
+		calls = append(calls, "high:"+event)
+	}, ListenPriority(10))
+
+	is.NoErr(c.Set("name", "app"))
+	is.Eq([]string{"high:set.value", "hook:set.value", "all:set.value", "low:set.value"}, calls)
+
+	calls = nil
+	is.NoErr(c.LoadStrings(JSON, `{"debug": true}`))
+	is.Eq([]string{"hook:load.data", "all:load.data"}, calls)
+
+	// unsubscribe
+	unsub()
+	unsub()
+	calls = nil
+	c.ClearData()
+	is.Eq([]string{"hook:clean.data"}, calls)
+}
+
+func TestConfig_Subscribe_panic(t *testing.T) {
+	is := assert.New(t)
+	c := New("test")
+
+	var called bool
+	c.Subscribe(OnSetValue, func(event string, c *Config) {
+		panic("listener error")
+	}, ListenPriority(1))
+	c.Subscribe(OnSetValue, func(event string, c *Config) {
+		called = true
+	})
+
+	is.NoErr(c.Set("name", "app"))
+	is.True(called)
+	is.Eq("app", c.String("name"))
+	is.ErrMsg(c.Error(), `config: the listener of the event "set.value" panic: listener error`)
+}
+
+func TestConfig_Subscribe_async(t *testing.T) {
+	is := assert.New(t)
+	c := New("test")
+
+	ch := make(chan string, 1)
+	c.Subscribe(OnSetValue, func(event string, c *Config) {
+		ch <- event
+	}, ListenAsync)
+
+	is.NoErr(c.Set("name", "app"))
+	select {
+	case event := <-ch:
+		is.Eq(OnSetValue, event)
+	case <-time.After(time.Second):
+		t.Fatal("the async listener is not called")
+	}
+}
+
+func TestConfig_Subscribe_asyncPanic(t *testing.T) {
+	is := assert.New(t)
+	c := New("test")
+
+	c.Subscribe(OnSetValue, func(event string, c *Config) {
+		panic("listener error")
+	}, ListenAsync)
+	is.NoErr(c.Set("name", "app"))
+
+	// read the error while the async listener is running
+	var err error
+	deadline := time.Now().Add(time.Second)
+	for err == nil && time.Now().Before(deadline) {
+		err = c.Error()
+	}
+	is.ErrMsg(err, `config: the listener of the event "set.value" panic: listener error`)
+	is.NoErr(c.Error())
+}
+
+func TestConfig_Subscribe_scope(t *testing.T) {
+	is := assert.New(t)
+	c := New("test")
+	is.NoErr(c.LoadStrings(JSON, `{"db": {"host": "localhost"}, "name": "app"}`))
+
+	var events []string
+	db := c.Scope("db")
+	db.Subscribe("*", func(event string, s *Config) {
+		is.Eq("db", s.Prefix())
+		events = append(events, event)
+	})
+	// subscribe again, the scope is only added once
+	db.Subscribe(OnDeleteValue, func(event string, s *Config) {
+		events = append(events, "again:"+event)
+	})
+
+	is.NoErr(c.Set("name", "app2"))
+	is.NoErr(db.Set("host", "db.local"))
+	is.NoErr(c.Delete("db.host"))
+	is.Eq([]string{OnSetValue, OnDeleteValue, "again:" + OnDeleteValue}, events)
+}
+
+func TestConfig_Subscribe_scopeRemoved(t *testing.T) {
+	is := assert.New(t)
+	c := New("test")
+
+	db := c.Scope("db")
+	off1 := db.Subscribe(OnSetValue, func(event string, s *Config) {})
+	off2 := db.Subscribe(OnDeleteValue, func(event string, s *Config) {})
+	is.Len(c.scopes, 1)
+
+	// the scope is removed after the last listener removed
+	off1()
+	is.Len(c.scopes, 1)
+	off2()
+	off2()
+	is.Len(c.scopes, 0)
+
+	// subscribe again
+	var called bool
+	db.Subscribe(OnSetValue, func(event string, s *Config) { called = true })
+	is.Len(c.scopes, 1)
+	is.NoErr(c.Set("db.host", "localhost"))
+	is.True(called)
+
+	// the scope with HookFunc is kept
+	app := c.Scope("app", WithHookFunc(func(event string, c *Config) {}))
+	app.Subscribe(OnSetValue, func(event string, s *Config) {})()
+	is.Len(c.scopes, 2)
+}
+
+func TestSaveFileOnSet_withHook(t *testing.T) {
+	is := assert.New(t)
+	file := t.TempDir() + "/config.json"
+
+	var hooked bool
+	c := New("test",
+		WithHookFunc(func(event string, c *Config) { hooked = true }),
+		SaveFileOnSet(file, JSON),
+	)
+
+	is.NoErr(c.Set("name", "app"))
+	is.True(hooked)
+	bs, err := os.ReadFile(file)
+	is.NoErr(err)
+	is.Contains(string(bs), `"name":"app"`)
+
+	// set on the scope view, will save the root data
+	is.NoErr(os.Remove(file))
+	db := c.Scope("db")
+	is.NoErr(db.Set("host", "localhost"))
+	bs, err = os.ReadFile(file)
+	is.NoErr(err)
+	is.Contains(string(bs), `"db":{"host":"localhost"}`)
+}
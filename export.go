@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"reflect"
 
 	"github.com/go-viper/mapstructure/v2"
 	"github.com/gookit/goutil/structs"
@@ -76,7 +77,8 @@ func (c *Config) Structure(key string, dst any) (err error) {
 	// binding all data on key is empty.
 	if key == "" {
 		// fix: if c.data is nil, don't need to apply map structure
-		if len(c.data) == 0 {
+		mp := c.Data()
+		if len(mp) == 0 {
 			// init default value by tag: default
 			if c.opts.ParseDefault {
 				err = structs.InitDefaults(dst, func(opt *structs.InitOptions) {
@@ -86,8 +88,7 @@ func (c *Config) Structure(key string, dst any) (err error) {
 			}
 			return
 		}
-
-		data = c.data
+		data = mp
 	} else {
 		// binding sub-data of the config
 		var ok bool
@@ -138,26 +139,31 @@ func (c *Config) WriteTo(out io.Writer) (n int64, err error) {
 }
 
 // DumpTo a writer and use format
-func DumpTo(out io.Writer, format string) (int64, error) { return dc.DumpTo(out, format) }
-
-// DumpTo use the format(json,yaml,toml) dump config data to a writer
-func (c *Config) DumpTo(out io.Writer, format string) (n int64, err error) {
-	var ok bool
-	var encoder Encoder
+func DumpTo(out io.Writer, format string, opts ...EncodeOptFn) (int64, error) {
+	return dc.DumpTo(out, format, opts...)
+}
 
+// DumpTo use the format(json,yaml,toml) dump config data to a writer.
+//
+// Usage:
+//
+//	c.DumpTo(buf, config.JSON, config.EncodeIndent("  "), config.EncodeKeyStyle(config.KeyStyleSnake))
+func (c *Config) DumpTo(out io.Writer, format string, opts ...EncodeOptFn) (n int64, err error) {
 	format = c.resolveFormat(format)
-	if encoder, ok = c.encoders[format]; !ok {
+	encoder := c.makeEncoder(format, c.opts.makeEncodeOptions(opts))
+	if encoder == nil {
 		err = errors.New("not exists/register encoder for the format: " + format)
 		return
 	}
 
 	// is empty
-	if len(c.data) == 0 {
+	data := c.rawData()
+	if len(data) == 0 {
 		return
 	}
 
 	// encode data to string
-	encoded, err := encoder(c.data)
+	encoded, err := encoder(data)
 	if err != nil {
 		return
 	}
@@ -168,16 +174,184 @@ func (c *Config) DumpTo(out io.Writer, format string) (n int64, err error) {
 }
 
 // DumpToFile use the format(json,yaml,toml) dump config data to a writer
-func (c *Config) DumpToFile(fileName string, format string) (err error) {
+func (c *Config) DumpToFile(fileName string, format string, opts ...EncodeOptFn) (err error) {
 	fsFlags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
 	f, err := os.OpenFile(fileName, fsFlags, os.ModePerm)
 	if err != nil {
 		return err
 	}
 
-	_, err = c.DumpTo(f, format)
+	_, err = c.DumpTo(f, format, opts...)
 	if err1 := f.Close(); err1 != nil && err == nil {
 		err = err1
 	}
 	return err
 }
+
+// DumpDocuments dump multi config instances as a multi documents content to the writer.
+//
+// The format driver must implement MultiDocsEncoder, eg: yaml.Driver.
+// will use the drivers of the first config instance.
+//
+// Usage:
+//
+//	cs, err := config.LoadDocuments(config.Yaml, content)
+//	_, err = config.DumpDocuments(buf, config.Yaml, cs...)
+func DumpDocuments(out io.Writer, format string, cs ...*Config) (int64, error) {
+	if len(cs) == 0 {
+		return 0, nil
+	}
+
+	c := cs[0]
+	format = c.resolveFormat(format)
+	d, ok := c.getDriver(format)
+	if !ok {
+		return 0, errors.New("not exists/register driver for the format: " + format)
+	}
+
+	me, ok := d.(MultiDocsEncoder)
+	if !ok {
+		return 0, errors.New("the driver does not support encode multi documents: " + format)
+	}
+
+	docs := make([]any, len(cs))
+	for i, sc := range cs {
+		docs[i] = sc.rawData()
+	}
+
+	encoded, err := me.EncodeDocs(docs)
+	if err != nil {
+		return 0, err
+	}
+
+	num, err := out.Write(encoded)
+	return int64(num), err
+}
+
+// make encoder for the format. will use OptionsEncoder if the driver supported.
+func (c *Config) makeEncoder(format string, eo *EncodeOptions) Encoder {
+	if eo == nil {
+		return c.getEncoder(format)
+	}
+
+	if d, ok := c.getDriver(format); ok {
+		if oe, ok := d.(OptionsEncoder); ok {
+			return func(v any) ([]byte, error) {
+				data, err := eo.prepareData(v)
+				if err != nil {
+					return nil, err
+				}
+				return oe.EncodeWith(data, eo)
+			}
+		}
+	}
+
+	encoder := c.getEncoder(format)
+	if encoder == nil {
+		return nil
+	}
+	return func(v any) ([]byte, error) {
+		data, err := eo.prepareData(v)
+		if err != nil {
+			return nil, err
+		}
+		return encoder(data)
+	}
+}
+
+// prepare data for encode, apply KeyStyle and OmitEmpty. will not modify the source data.
+//
+// Will return error if the keys of a map are conflicted after convert by KeyStyle. eg: "db_host" and "dbHost"
+func (eo *EncodeOptions) prepareData(v any) (any, error) {
+	if eo.KeyStyle == KeyStyleKeep && !eo.OmitEmpty {
+		return v, nil
+	}
+
+	val, _, err := eo.prepareValue(v)
+	return val, err
+}
+
+// return new value and check it is empty.
+func (eo *EncodeOptions) prepareValue(v any) (any, bool, error) {
+	switch tv := v.(type) {
+	case nil:
+		return nil, true, nil
+	case string:
+		return tv, tv == "", nil
+	case map[string]any:
+		mp := make(map[string]any, len(tv))
+		for k, sv := range tv {
+			if err := eo.addMapValue(mp, k, sv); err != nil {
+				return nil, false, err
+			}
+		}
+		return mp, len(mp) == 0, nil
+	case map[any]any:
+		mp := make(map[string]any, len(tv))
+		for k, sv := range tv {
+			if err := eo.addMapValue(mp, fmt.Sprint(k), sv); err != nil {
+				return nil, false, err
+			}
+		}
+		return mp, len(mp) == 0, nil
+	case []any:
+		list := make([]any, 0, len(tv))
+		for _, sv := range tv {
+			nv, _, err := eo.prepareValue(sv)
+			if err != nil {
+				return nil, false, err
+			}
+			list = append(list, nv)
+		}
+		return list, len(list) == 0, nil
+	}
+
+	// other map and slice types. eg: map[string]string, []string, []map[string]any
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return v, rv.Len() == 0, nil
+		}
+
+		mp := make(map[string]any, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			if err := eo.addMapValue(mp, iter.Key().String(), iter.Value().Interface()); err != nil {
+				return nil, false, err
+			}
+		}
+		return mp, len(mp) == 0, nil
+	case reflect.Slice, reflect.Array:
+		// the elements may contain maps. eg: []map[string]any
+		switch rv.Type().Elem().Kind() {
+		case reflect.Map, reflect.Slice, reflect.Array, reflect.Interface:
+			list := make([]any, 0, rv.Len())
+			for i := 0; i < rv.Len(); i++ {
+				nv, _, err := eo.prepareValue(rv.Index(i).Interface())
+				if err != nil {
+					return nil, false, err
+				}
+				list = append(list, nv)
+			}
+			return list, len(list) == 0, nil
+		}
+		return v, rv.Len() == 0, nil
+	}
+	return v, false, nil
+}
+
+// prepare the value and add it to the map by the formatted key.
+func (eo *EncodeOptions) addMapValue(mp map[string]any, key string, val any) error {
+	nv, empty, err := eo.prepareValue(val)
+	if err != nil || empty && eo.OmitEmpty {
+		return err
+	}
+
+	nk := eo.KeyStyle.Format(key)
+	if _, ok := mp[nk]; ok {
+		return fmt.Errorf("config: the map keys are conflicted after convert to %q by the key style %q", nk, eo.KeyStyle)
+	}
+	mp[nk] = nv
+	return nil
+}
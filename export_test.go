@@ -373,3 +373,88 @@ func TestConfig_DumpToFile(t *testing.T) {
 	assert.Err(t, err)
 	assert.ErrMsg(t, err, "not exists/register encoder for the format: yaml")
 }
+
+func TestConfig_DumpTo_encodeOptions(t *testing.T) {
+	is := assert.New(t)
+	c := New("test", WithEncodeOptions(EncodeKeyStyle(KeyStyleSnake)))
+	err := c.LoadData(map[string]any{
+		"appName": "demo",
+		"empty":   "",
+		"dbConfig": map[string]any{
+			"maxConn": 10,
+			"tags":    []any{},
+		},
+	})
+	is.NoErr(err)
+
+	buf := &bytes.Buffer{}
+	_, err = c.DumpTo(buf, JSON, EncodeOmitEmpty, EncodeIndent("  "))
+	is.NoErr(err)
+	str := buf.String()
+	is.StrContains(str, `"app_name": "demo"`)
+	is.StrContains(str, `"max_conn": 10`)
+	is.NotContains(str, "empty")
+	is.NotContains(str, "tags")
+	// source data not changed
+	is.Eq("demo", c.String("appName"))
+	is.Eq("", c.String("empty"))
+
+	// default options from Config.Options
+	buf.Reset()
+	_, err = c.DumpTo(buf, JSON)
+	is.NoErr(err)
+	is.StrContains(buf.String(), `"db_config":{`)
+	is.StrContains(buf.String(), `"empty":""`)
+
+	// options passed to OptionsEncoder
+	drv := &streamDriver{StdDriver: NewDriver("stream", JSONDecoder, JSONEncoder)}
+	c.AddDriverV2(drv)
+	buf.Reset()
+	_, err = c.DumpTo(buf, "stream", EncodeLineWidth(80), EncodeSortKeys)
+	is.NoErr(err)
+	is.NotNil(drv.encodeOpt)
+	is.Eq(80, drv.encodeOpt.LineWidth)
+	is.True(drv.encodeOpt.SortKeys)
+	is.Eq(KeyStyleSnake, drv.encodeOpt.KeyStyle)
+	// default options not changed
+	is.Eq(0, c.Options().EncodeOptions.LineWidth)
+	is.False(c.Options().EncodeOptions.SortKeys)
+}
+
+func TestConfig_DumpTo_encodeOptions_nested(t *testing.T) {
+	is := assert.New(t)
+
+	// the maps in the typed slice
+	c := New("test")
+	is.NoErr(c.Set("servers", []map[string]any{{"hostName": "a", "empty": ""}}, false))
+
+	buf := &bytes.Buffer{}
+	_, err := c.DumpTo(buf, JSON, EncodeKeyStyle(KeyStyleSnake), EncodeOmitEmpty)
+	is.NoErr(err)
+	is.Eq(`{"servers":[{"host_name":"a"}]}`+"\n", buf.String())
+
+	// the keys are conflicted after convert
+	c = New("test")
+	is.NoErr(c.LoadData(map[string]any{"dbHost": "a", "db_host": "b"}))
+	_, err = c.DumpTo(buf, JSON, EncodeKeyStyle(KeyStyleSnake))
+	is.ErrMsg(err, `config: the map keys are conflicted after convert to "db_host" by the key style "snake"`)
+}
+
+func TestKeyStyle_Format(t *testing.T) {
+	is := assert.New(t)
+
+	tests := []struct{ in, snake, kebab, camel string }{
+		{"dbHost", "db_host", "db-host", "dbHost"},
+		{"DB_HOST", "db_host", "db-host", "dbHost"},
+		{"db-host", "db_host", "db-host", "dbHost"},
+		{"HTTPServer", "http_server", "http-server", "httpServer"},
+		{"user_id2", "user_id2", "user-id2", "userId2"},
+		{"name", "name", "name", "name"},
+	}
+	for _, tt := range tests {
+		is.Eq(tt.snake, KeyStyleSnake.Format(tt.in))
+		is.Eq(tt.kebab, KeyStyleKebab.Format(tt.in))
+		is.Eq(tt.camel, KeyStyleCamel.Format(tt.in))
+		is.Eq(tt.in, KeyStyleKeep.Format(tt.in))
+	}
+}
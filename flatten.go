@@ -0,0 +1,127 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Flatten get the flat data of the config. see Config.Flatten()
+func Flatten(sep byte) map[string]any { return dc.Flatten(sep) }
+
+// Flatten get the flat data with the full key paths of all leaf values, keys are joined by the sep.
+// if sep is 0, will use the Options.Delimiter. the empty map or slice is a leaf value.
+//
+// The keys contain the sep will be quoted. eg: `hosts["db.local"].port`
+//
+// Usage:
+//
+//	flat := c.Flatten('.')
+//	// {"db.master.host": "localhost", "servers.0.port": 80}
+func (c *Config) Flatten(sep byte) map[string]any {
+	if sep == 0 {
+		sep = c.opts.Delimiter
+	}
+
+	data := deepCopy(c.rawData())
+
+	flat := make(map[string]any)
+	_ = walkValue(data, "", 0, sep, func(path string, value any, _ int) error {
+		if !hasChildren(value) {
+			flat[path] = value
+		}
+		return nil
+	})
+	return flat
+}
+
+// flatNode the map node created by Unflatten()
+type flatNode map[string]any
+
+// Unflatten rebuild the nested data from the flat data, it is the reverse of Flatten().
+// the default sep is '.'
+//
+// The continuous index keys from 0 will be converted to slice. eg: "servers.0.port", "servers.1.port"
+// NOTE: only expand the top keys of the flat data, the nested map values are kept as is.
+//
+// Usage:
+//
+//	data, err := config.Unflatten(map[string]any{"db.host": "localhost", "tags.0": "a"})
+//	// {"db": {"host": "localhost"}, "tags": ["a"]}
+func Unflatten(flat map[string]any, sep ...byte) (map[string]any, error) {
+	sepChar := byte(defaultDelimiter)
+	if len(sep) > 0 && sep[0] != 0 {
+		sepChar = sep[0]
+	}
+
+	root := flatNode{}
+	for _, key := range sortedKeys(flat) {
+		keys, err := parsePath(key, sepChar)
+		if err != nil {
+			return nil, err
+		}
+
+		node := root
+		for i, k := range keys[:len(keys)-1] {
+			switch child := node[k].(type) {
+			case nil:
+				newNode := flatNode{}
+				node[k], node = newNode, newNode
+			case flatNode:
+				node = child
+			case map[string]any:
+				newNode := flatNode(deepCopy(child).(map[string]any))
+				node[k], node = newNode, newNode
+			default:
+				return nil, unflattenError(key, keys[:i+1], sepChar)
+			}
+		}
+
+		last := keys[len(keys)-1]
+		if old, ok := node[last]; ok && old != nil {
+			return nil, unflattenError(key, keys, sepChar)
+		}
+		node[last] = deepCopy(flat[key])
+	}
+	return root.toMap(), nil
+}
+
+func unflattenError(key string, keys []string, sep byte) error {
+	return fmt.Errorf("config: cannot unflatten the key %q, the key %q already has a value", key, keysToPath(keys, sep))
+}
+
+// convert the node and children to map[string]any
+func (n flatNode) toMap() map[string]any {
+	for k, v := range n {
+		if child, ok := v.(flatNode); ok {
+			n[k] = child.toData()
+		}
+	}
+	return n
+}
+
+// convert the node to map[string]any, the node with continuous index keys will be converted to []any
+func (n flatNode) toData() any {
+	n.toMap()
+	if n.isList() {
+		list := make([]any, len(n))
+		for i := range list {
+			list[i] = n[strconv.Itoa(i)]
+		}
+		return list
+	}
+	return map[string]any(n)
+}
+
+// check the keys are continuous index from 0
+func (n flatNode) isList() bool {
+	if len(n) == 0 {
+		return false
+	}
+
+	for i := 0; i < len(n); i++ {
+		if _, ok := n[strconv.Itoa(i)]; !ok {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,111 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/gookit/goutil/testutil/assert"
+)
+
+func TestConfig_Flatten(t *testing.T) {
+	is := assert.New(t)
+	c := New("test")
+	c.SetData(map[string]any{
+		"name": "app",
+		"db":   map[string]any{"master": map[string]any{"host": "localhost"}},
+		"servers": []any{
+			map[string]any{"port": 80},
+			map[any]any{"port": 443},
+		},
+		"tags":  []string{"a"},
+		"empty": []any{},
+		"hosts": map[string]any{"db.local": 1},
+	})
+
+	flat := c.Flatten('.')
+	is.Eq(map[string]any{
+		"name":              "app",
+		"db.master.host":    "localhost",
+		"servers.0.port":    80,
+		"servers.1.port":    443,
+		"tags.0":            "a",
+		"empty":             []any{},
+		`hosts["db.local"]`: 1,
+	}, flat)
+
+	is.Eq("localhost", c.Flatten(':')["db:master:host"])
+	is.Eq("localhost", c.Flatten(0)["db.master.host"])
+	is.Eq(map[string]any{"host": "localhost"}, c.Scope("db.master").Flatten(0))
+
+	// round trip
+	data, err := Unflatten(flat)
+	is.NoErr(err)
+	is.Eq(map[string]any{
+		"name":    "app",
+		"db":      map[string]any{"master": map[string]any{"host": "localhost"}},
+		"servers": []any{map[string]any{"port": 80}, map[string]any{"port": 443}},
+		"tags":    []any{"a"},
+		"empty":   []any{},
+		"hosts":   map[string]any{"db.local": 1},
+	}, data)
+}
+
+func TestUnflatten(t *testing.T) {
+	is := assert.New(t)
+
+	src := map[string]any{
+		"a":      map[string]any{"x": 1},
+		"a.b":    2,
+		"list.1": "b",
+		"list.0": "a",
+		"idx.1":  "not a list",
+		"0":      "top",
+	}
+	data, err := Unflatten(src)
+	is.NoErr(err)
+	is.Eq(map[string]any{
+		"a":    map[string]any{"x": 1, "b": 2},
+		"list": []any{"a", "b"},
+		"idx":  map[string]any{"1": "not a list"},
+		"0":    "top",
+	}, data)
+	// the source data is not changed
+	is.Eq(map[string]any{"x": 1}, src["a"])
+
+	data, err = Unflatten(map[string]any{"a:b": 1}, ':')
+	is.NoErr(err)
+	is.Eq(map[string]any{"a": map[string]any{"b": 1}}, data)
+
+	_, err = Unflatten(map[string]any{"a": 1, "a.b": 2})
+	is.ErrMsg(err, `config: cannot unflatten the key "a.b", the key "a" already has a value`)
+	_, err = Unflatten(map[string]any{"a": map[string]any{"b": 1}, "a.b": 2})
+	is.ErrSubMsg(err, `the key "a.b" already has a value`)
+	_, err = Unflatten(map[string]any{"a[": 1})
+	is.ErrSubMsg(err, "invalid key path")
+}
+
+func TestConfig_ExpandKeys(t *testing.T) {
+	is := assert.New(t)
+
+	// default: keep the dotted keys
+	c := New("test")
+	c.LoadSMap(map[string]string{"db.host": "localhost"})
+	is.Eq("localhost", c.Get("db.host", false))
+	is.False(c.Exists("db"))
+
+	c = New("test", ExpandKeys)
+	c.LoadSMap(map[string]string{"db.host": "localhost", "db.port": "3306"})
+	is.NoErr(c.Error())
+	is.Eq(map[string]any{"host": "localhost", "port": "3306"}, c.Get("db"))
+
+	err := c.LoadData(map[string]any{"db.user": "root", "tags.0": "a"}, map[string]string{"app.name": "demo"})
+	is.NoErr(err)
+	is.Eq("root", c.String("db.user"))
+	is.Eq("localhost", c.String("db.host"))
+	is.Eq([]string{"a"}, c.Strings("tags"))
+	is.Eq("demo", c.String("app.name"))
+
+	err = c.LoadData(map[string]any{"x": 1, "x.y": 2})
+	is.ErrSubMsg(err, "cannot unflatten")
+	c.LoadSMap(map[string]string{"x": "1", "x.y": "2"})
+	is.ErrSubMsg(c.Error(), "cannot unflatten")
+}
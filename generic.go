@@ -0,0 +1,91 @@
+package config
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/go-viper/mapstructure/v2"
+	"github.com/gookit/goutil/reflects"
+	"github.com/gookit/goutil/strutil"
+)
+
+// GetAs get value by key and convert it to the type T.
+// return the default value or zero value of T on not found or convert failed.
+//
+// Usage:
+//
+//	port := config.GetAs[uint16](c, "server.port", 8080)
+//	ratios := config.GetAs[[]float64](c, "ratios")
+func GetAs[T any](c *Config, key string, def ...T) T {
+	val, err := GetAsE[T](c, key)
+	if err != nil && len(def) > 0 {
+		return def[0]
+	}
+	return val
+}
+
+// GetAsE get value by key and convert it to the type T, will return error on not found or convert failed.
+// The error is not recorded to Config.Error().
+//
+// The conversion is same as Config.Structure(), use mapstructure and the decode hooks by Options.
+// time.Duration and time.Time can always be converted from string. eg: "10s", "2024-01-02 15:04:05"
+//
+// Return error:
+//   - the errors of Config.GetValueE(), eg: *NotFoundError, *PathError
+//   - *ConvertError the value cannot be converted to the type T
+func GetAsE[T any](c *Config, key string) (T, error) {
+	var dst T
+	val, err := c.GetValueE(key)
+	if err != nil {
+		return dst, err
+	}
+
+	if err := c.decodeValue(val, &dst); err != nil {
+		return dst, &ConvertError{Key: key, Value: val, Type: reflect.TypeOf(&dst).Elem().String(), Err: err}
+	}
+	return dst, nil
+}
+
+// MustGet get value by key and convert it to the type T, will panic on not found or convert failed.
+func MustGet[T any](c *Config, key string) T {
+	val, err := GetAsE[T](c, key)
+	if err != nil {
+		panic(err)
+	}
+	return val
+}
+
+// decode the value to ptr by mapstructure, use the same config as Structure().
+func (c *Config) decodeValue(val, ptr any) error {
+	bindConf := c.opts.makeDecoderConfig()
+	bindConf.Result = ptr
+	if bindConf.DecodeHook == nil {
+		bindConf.DecodeHook = timeDecodeHook
+	} else {
+		bindConf.DecodeHook = mapstructure.ComposeDecodeHookFunc(bindConf.DecodeHook, timeDecodeHook)
+	}
+
+	decoder, err := mapstructure.NewDecoder(bindConf)
+	if err != nil {
+		return err
+	}
+	return decoder.Decode(val)
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// convert string to time.Duration or time.Time, if the target type is it.
+func timeDecodeHook(f reflect.Type, t reflect.Type, data any) (any, error) {
+	if f.Kind() != reflect.String {
+		return data, nil
+	}
+
+	str := data.(string)
+	if t == durationType {
+		return strutil.ToDuration(str)
+	}
+	if reflects.IsTimeType(t) {
+		return strutil.ToTime(str)
+	}
+	return data, nil
+}
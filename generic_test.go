@@ -0,0 +1,98 @@
+package config
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gookit/goutil/testutil"
+	"github.com/gookit/goutil/testutil/assert"
+)
+
+func TestGetAs(t *testing.T) {
+	is := assert.New(t)
+
+	c := New("test")
+	err := c.LoadStrings(JSON, `{
+"port": "8080",
+"ratio": 0.5,
+"ratios": [0.1, "0.2", 3],
+"flags": [true, "false", 1],
+"features": {"a": true, "b": "false"},
+"timeout": "1m30s",
+"ttl": 15,
+"created": "2024-01-02 15:04:05",
+"envName": "${APP_NAME | app}",
+"db": {"host": "localhost", "port": 3306}
+}`)
+	is.NoErr(err)
+
+	is.Eq(uint16(8080), GetAs[uint16](c, "port"))
+	is.Eq(float32(0.5), GetAs[float32](c, "ratio"))
+	is.Eq([]float64{0.1, 0.2, 3}, GetAs[[]float64](c, "ratios"))
+	is.Eq([]bool{true, false, true}, GetAs[[]bool](c, "flags"))
+	is.Eq(map[string]bool{"a": true, "b": false}, GetAs[map[string]bool](c, "features"))
+	is.Eq(90*time.Second, GetAs[time.Duration](c, "timeout"))
+	is.Eq(time.Duration(15), GetAs[time.Duration](c, "ttl"))
+	is.Eq(2024, GetAs[time.Time](c, "created").Year())
+	is.Eq("localhost", GetAs[map[string]string](c, "db")["host"])
+	is.Eq("3306", GetAs[string](c, "db.port"))
+
+	// default value
+	is.Eq(23, GetAs[int](c, "not-exists", 23))
+	is.Eq(0, GetAs[int](c, "not-exists"))
+	is.Eq(23, GetAs[int](c, "db.host", 23))
+
+	// struct
+	type db struct {
+		Host string
+		Port int
+	}
+	is.Eq(db{Host: "localhost", Port: 3306}, GetAs[db](c, "db"))
+
+	// with ParseEnv
+	is.Eq("${APP_NAME | app}", GetAs[string](c, "envName"))
+	c = New("test", ParseEnv)
+	is.NoErr(c.LoadData(map[string]any{"envName": "${APP_NAME | app}", "big": uint64(18446744073709551615)}))
+	testutil.MockEnvValue("APP_NAME", "my-app", func(_ string) {
+		is.Eq("my-app", GetAs[string](c, "envName"))
+	})
+	is.Eq(uint64(18446744073709551615), GetAs[uint64](c, "big"))
+}
+
+func TestGetAsE_MustGet(t *testing.T) {
+	is := assert.New(t)
+
+	c := New("test")
+	is.NoErr(c.LoadData(map[string]any{"name": "app", "age": 23}))
+
+	age, err := GetAsE[int8](c, "age")
+	is.NoErr(err)
+	is.Eq(int8(23), age)
+
+	_, err = GetAsE[int](c, "not-exists")
+	is.ErrIs(err, ErrNotFound)
+	var nfErr *NotFoundError
+	is.True(errors.As(err, &nfErr))
+	is.Eq("not-exists", nfErr.Key)
+
+	_, err = GetAsE[int](c, "name.sub")
+	var pathErr *PathError
+	is.True(errors.As(err, &pathErr))
+	is.Eq("name", pathErr.Path)
+
+	_, err = GetAsE[int](c, "name")
+	is.ErrSubMsg(err, "config: cannot convert value \"app\"(string) of the key 'name' to int")
+	var convErr *ConvertError
+	is.True(errors.As(err, &convErr))
+	is.Eq("name", convErr.Key)
+	is.Eq("int", convErr.Type)
+	is.Err(convErr.Err)
+	// the errors are not recorded
+	is.NoErr(c.Error())
+
+	is.Eq("app", MustGet[string](c, "name"))
+	is.Panics(func() {
+		MustGet[[]int](c, "not-exists")
+	})
+}
@@ -0,0 +1,206 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gookit/goutil/testutil/assert"
+)
+
+// run the fn with a timeout, for check the deadlock
+func runWithTimeout(t *testing.T, fn func()) {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		fn()
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(3 * time.Second):
+		t.Fatal("timeout: the hook is deadlocked")
+	}
+}
+
+func TestHook_readConfig(t *testing.T) {
+	is := assert.New(t)
+	file := t.TempDir() + "/app.json"
+	is.NoErr(os.WriteFile(file, []byte(`{"name": "app", "db": {"host": "localhost"}}`), 0644))
+
+	var got []string
+	c := New("test", WithHookFunc(func(event string, c *Config) {
+		got = append(got, event+":"+c.String("name")+":"+c.String("db.host"))
+		_ = c.Data()
+		_ = c.Exists("db.host")
+	}))
+	c.OnChange("", func(ev ChangeEvent) {
+		_ = c.String("db.host")
+		_, _ = c.Query("db.*")
+	})
+
+	runWithTimeout(t, func() {
+		is.NoErr(c.LoadFiles(file))
+		is.NoErr(c.Set("db.host", "db.local"))
+		is.NoErr(c.Delete("db.host"))
+		is.NoErr(c.LoadData(map[string]any{"name": "app2"}))
+		is.NoErr(c.ReloadFiles())
+		c.SetData(map[string]any{"name": "app3"})
+		c.ClearData()
+	})
+
+	is.Eq([]string{
+		"load.data:app:localhost",
+		"set.value:app:db.local",
+		"delete.value:app:",
+		"load.data:app2:",
+		"reload.data:app:localhost",
+		"set.data:app3:",
+		"clean.data::",
+	}, got)
+}
+
+func TestHook_writeConfig(t *testing.T) {
+	is := assert.New(t)
+	c := New("test")
+
+	var got []string
+	c.Subscribe(OnSetValue, func(event string, c *Config) {
+		got = append(got, "hook:"+strconv.Itoa(c.Int("count")))
+		// write in the hook, the event will be delivered after the current event
+		if n := c.Int("count"); n < 3 {
+			is.NoErr(c.Set("count", n+1))
+			got = append(got, "nested-set:"+strconv.Itoa(n+1))
+		}
+	})
+	c.OnChange("count", func(ev ChangeEvent) {
+		got = append(got, "change:"+strconv.Itoa(ev.New.(int)))
+	})
+
+	runWithTimeout(t, func() {
+		is.NoErr(c.Set("count", 1))
+	})
+
+	is.Eq(3, c.Int("count"))
+	is.Eq([]string{
+		"hook:1", "nested-set:2", "change:1",
+		"hook:2", "nested-set:3", "change:2",
+		"hook:3", "change:3",
+	}, got)
+}
+
+func TestHook_scopeView(t *testing.T) {
+	is := assert.New(t)
+	c := New("test")
+	is.NoErr(c.LoadStrings(JSON, `{"db": {"host": "localhost", "port": 3306}}`))
+
+	var got []string
+	db := c.Scope("db", WithHookFunc(func(event string, s *Config) {
+		got = append(got, event+":"+s.String("host"))
+		// write and subscribe in the hook
+		if s.Int("port") == 3306 {
+			is.NoErr(s.Set("port", 3307))
+			s.Subscribe(OnDeleteValue, func(event string, s *Config) {
+				got = append(got, "sub:"+event)
+			})
+		}
+	}))
+
+	runWithTimeout(t, func() {
+		is.NoErr(db.Set("host", "db.local"))
+		is.NoErr(c.Delete("db.port"))
+	})
+
+	is.Eq([]string{
+		"set.value:db.local",
+		"set.value:db.local",
+		"delete.value:db.local",
+		"sub:delete.value",
+	}, got)
+}
+
+func TestHook_concurrentOrder(t *testing.T) {
+	is := assert.New(t)
+	c := New("test")
+
+	var mu sync.Mutex
+	values := make(map[string][]int)
+	c.OnChange("", func(ev ChangeEvent) {
+		_ = c.Int(ev.Path)
+
+		mu.Lock()
+		values[ev.Path] = append(values[ev.Path], ev.New.(int))
+		mu.Unlock()
+	})
+
+	var wg sync.WaitGroup
+	runWithTimeout(t, func() {
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func(key string) {
+				defer wg.Done()
+				for n := 1; n <= 50; n++ {
+					if err := c.Set(key, n); err != nil {
+						t.Error(err)
+					}
+				}
+			}("key" + strconv.Itoa(i))
+		}
+		wg.Wait()
+	})
+
+	// the events of each key are delivered in the order of the changes
+	mu.Lock()
+	defer mu.Unlock()
+	is.Len(values, 4)
+	for _, list := range values {
+		is.Len(list, 50)
+		for i, n := range list {
+			is.Eq(i+1, n)
+		}
+	}
+}
+
+func TestHook_deliveredByOtherGoroutine(t *testing.T) {
+	is := assert.New(t)
+
+	started, release := make(chan struct{}), make(chan struct{})
+	var mu sync.Mutex
+	var events []string
+	c := New("test", WithHookFunc(func(event string, c *Config) {
+		mu.Lock()
+		events = append(events, event)
+		mu.Unlock()
+
+		if event == OnSetValue && c.String("name") == "blocked" {
+			close(started)
+			<-release
+		}
+	}))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if err := c.Set("name", "blocked"); err != nil {
+			t.Error(err)
+		}
+	}()
+	<-started
+
+	// the events are delivering by other goroutine, Set returns before its hook called
+	runWithTimeout(t, func() {
+		is.NoErr(c.Delete("name"))
+	})
+	mu.Lock()
+	is.Eq([]string{OnSetValue}, events)
+	mu.Unlock()
+
+	// the delivering goroutine calls the hook later
+	close(release)
+	<-done
+	mu.Lock()
+	is.Eq([]string{OnSetValue, OnDeleteValue}, events)
+	mu.Unlock()
+}
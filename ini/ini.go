@@ -6,6 +6,10 @@ about ini parse, please see https://github.com/gookit/ini/parser
 package ini
 
 import (
+	"bufio"
+	"bytes"
+	"strings"
+
 	"github.com/gookit/config/v2"
 	"github.com/gookit/ini/v2/parser"
 )
@@ -17,4 +21,111 @@ var Decoder config.Decoder = parser.Decode
 var Encoder config.Encoder = parser.Encode
 
 // Driver for ini
-var Driver = config.NewDriver(config.Ini, Decoder, Encoder)
+var Driver = &iniDriver{StdDriver: config.NewDriver(config.Ini, Decoder, Encoder)}
+
+// DefaultListSep the default list separator of the typed decoder. eg: `ports = 80, 443`
+const DefaultListSep = ","
+
+// TypedDecoder the ini content decoder with typed values. see DecodeTyped
+var TypedDecoder config.Decoder = DecodeTyped
+
+// TypedDriver for ini, will infer value types on decode. see DecodeTyped
+//
+// Usage:
+//
+//	config.AddDriver(ini.TypedDriver)
+var TypedDriver = &iniDriver{
+	StdDriver: config.NewDriver(config.Ini, TypedDecoder, Encoder),
+	typed:     true,
+}
+
+// iniDriver support collect comments and detect INI content.
+type iniDriver struct {
+	*config.StdDriver
+	typed bool
+}
+
+// DecodeWithComments decode INI content and collect comments.
+//
+// The comments key format: "section_key", section comments key: "_sec_section"
+func (d *iniDriver) DecodeWithComments(blob []byte, v any) (map[string]string, error) {
+	if d.typed {
+		return decodeTyped(blob, v, DefaultListSep)
+	}
+
+	p, err := parser.Parse(string(blob), parser.ModeFull, parser.NoDefSection)
+	if err != nil {
+		return nil, err
+	}
+	return p.Comments(), p.MapStruct(v)
+}
+
+// Sniff check the first valid line is a section or key-value line.
+func (d *iniDriver) Sniff(blob []byte) bool {
+	s := bufio.NewScanner(bytes.NewReader(blob))
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if line == "" || parser.IsCommentChar(line[0]) {
+			continue
+		}
+
+		if line[0] == '[' {
+			return line[len(line)-1] == ']'
+		}
+		return strings.IndexByte(line, '=') > 0
+	}
+	return false
+}
+
+// DecodeTyped decode INI content with typed values.
+//
+//   - value will infer to bool, int, float and list. eg: `[a, b]`, `a, b`, see config.InferValue
+//   - section and key name will be split by '.' to nested maps. eg: `[db.master]`
+//   - the key ends with "[]" will collect values to a slice. eg: `arr[] = val`
+//
+// NOTE: the value contains DefaultListSep will be split to list. eg: `desc = Hello, world`,
+// use NewTypedDecoder("") to disable it.
+func DecodeTyped(blob []byte, ptr any) error {
+	return NewTypedDecoder(DefaultListSep)(blob, ptr)
+}
+
+// NewTypedDecoder create a typed INI decoder with custom list separator.
+// if listSep is not empty, will split the value contains it to list. eg: "," for `ports = 80, 443`
+func NewTypedDecoder(listSep string) config.Decoder {
+	return func(blob []byte, ptr any) error {
+		_, err := decodeTyped(blob, ptr, listSep)
+		return err
+	}
+}
+
+func decodeTyped(blob []byte, ptr any, listSep string) (map[string]string, error) {
+	var err error
+	data := make(map[string]any)
+
+	p := parser.New(parser.WithParseMode(parser.ModeFull), func(opt *parser.Options) {
+		opt.Collector = func(section, key, val string, isSlice bool) {
+			if err != nil {
+				return
+			}
+
+			keys := strings.Split(key, ".")
+			if section != opt.DefSection {
+				keys = append(strings.Split(section, "."), keys...)
+			}
+
+			if isSlice {
+				err = config.CollectValue(data, keys, config.InferValue(val, ""), true)
+			} else {
+				err = config.CollectValue(data, keys, config.InferValue(val, listSep), false)
+			}
+		}
+	})
+
+	if err1 := p.ParseBytes(blob); err1 != nil {
+		return nil, err1
+	}
+	if err != nil {
+		return nil, err
+	}
+	return p.Comments(), config.DecodeMapTo(data, ptr, parser.TagName)
+}
@@ -72,3 +72,99 @@ func TestDriver(t *testing.T) {
 	_, err = Encoder("invalid")
 	st.Err(err)
 }
+
+func TestDecodeTyped(t *testing.T) {
+	is := assert.New(t)
+
+	c := config.NewEmpty("test")
+	c.AddDriver(TypedDriver)
+
+	err := c.LoadStrings(config.Ini, `
+name = app
+debug = true
+age = 23
+rate = 0.5
+code = 007
+ports = 80, 443
+tags2 = [a, b]
+tags = [go, php]
+arr[] = 1
+arr[] = two
+
+[db.master]
+host = localhost
+port = 3306
+`)
+	is.NoErr(err)
+	is.Eq("app", c.Get("name"))
+	is.Eq(true, c.Get("debug"))
+	is.True(c.Bool("debug"))
+	is.Eq(23, c.Get("age"))
+	is.Eq(0.5, c.Float("rate"))
+	is.Eq("007", c.Get("code"))
+	is.Eq([]int{80, 443}, c.Ints("ports"))
+	is.Eq([]any{80, 443}, c.Get("ports"))
+	is.Eq([]string{"a", "b"}, c.Strings("tags2"))
+	is.Eq([]string{"go", "php"}, c.Strings("tags"))
+	is.Eq([]any{1, "two"}, c.Get("arr"))
+	is.Eq("localhost", c.String("db.master.host"))
+	is.Eq(3306, c.Get("db.master.port"))
+
+	type master struct {
+		Host  string
+		Port  int
+		Ports []int
+	}
+	m := &master{}
+	is.NoErr(c.Structure("db.master", m))
+	is.Eq(3306, m.Port)
+
+	// decode to struct
+	m = &master{}
+	err = TypedDecoder([]byte("host = local\nport = 33\nports = [1,2]"), m)
+	is.NoErr(err)
+	is.Eq("local", m.Host)
+	is.Eq([]int{1, 2}, m.Ports)
+
+	// disable split list by the separator
+	mp := map[string]any{}
+	err = NewTypedDecoder("")([]byte("ports = 80, 443\ndesc = Hello, world\ntags = [a, b]"), &mp)
+	is.NoErr(err)
+	is.Eq("80, 443", mp["ports"])
+	is.Eq("Hello, world", mp["desc"])
+	is.Eq([]any{"a", "b"}, mp["tags"])
+
+	// conflict: section is a value, in any order
+	err = TypedDecoder([]byte("db = val\n[db.master]\nhost = localhost"), &mp)
+	is.ErrSubMsg(err, `"db" is not a map`)
+	err = TypedDecoder([]byte("[db.master]\nhost = localhost\n[__default]\ndb = val"), &map[string]any{})
+	is.ErrSubMsg(err, `cannot set value for "db", it is a map`)
+	err = TypedDecoder([]byte("arr = val\narr[] = val1"), &map[string]any{})
+	is.ErrSubMsg(err, `"arr", it is not a slice`)
+}
+
+func TestDriver_comments(t *testing.T) {
+	is := assert.New(t)
+
+	c := config.NewEmpty("test")
+	c.AddDriver(Driver)
+	err := c.LoadStrings(config.Ini, `
+; the app name
+name = app
+`)
+	is.NoErr(err)
+	is.Eq("app", c.String("name"))
+	is.StrContains(c.Comments()["__default_name"], "the app name")
+
+	// detect format by content
+	is.True(Driver.Sniff([]byte("; comments\n[db]\nhost = localhost")))
+	is.True(Driver.Sniff([]byte("name = app")))
+	is.False(Driver.Sniff([]byte(`{"name": "app"}`)))
+
+	c = config.NewEmpty("test")
+	c.AddDriver(TypedDriver)
+	err = c.LoadStrings("", "# the port\nport = 80")
+	is.NoErr(err)
+	is.Eq(80, c.Get("port"))
+	is.NotEmpty(c.Comments())
+}
@@ -20,5 +20,17 @@ var (
 	// Encoder for json
 	Encoder config.Encoder = json.Marshal
 	// Driver for json
-	Driver = config.NewDriver(config.JSON, Decoder, Encoder)
+	Driver = &jsonDriver{StdDriver: config.NewDriver(config.JSON, Decoder, Encoder)}
 )
+
+type jsonDriver struct {
+	*config.StdDriver
+}
+
+// EncodeWith encode data with config.EncodeOptions. Indent is supported.
+func (d *jsonDriver) EncodeWith(v any, opts *config.EncodeOptions) ([]byte, error) {
+	if opts == nil || opts.Indent == "" {
+		return d.Encode(v)
+	}
+	return json.MarshalIndent(v, "", opts.Indent)
+}
@@ -96,3 +96,19 @@ func TestDriver(t *testing.T) {
 
 	config.JSONAllowComments = old
 }
+
+func TestDriver_EncodeWith(t *testing.T) {
+	is := assert.New(t)
+
+	c := config.NewEmpty("test")
+	c.AddDriver(Driver)
+	is.NoErr(c.LoadData(map[string]any{"name": "app"}))
+
+	bs, err := Driver.EncodeWith(c.Data(), &config.EncodeOptions{Indent: "  "})
+	is.NoErr(err)
+	is.Eq("{\n  \"name\": \"app\"\n}", string(bs))
+
+	bs, err = Driver.EncodeWith(c.Data(), nil)
+	is.NoErr(err)
+	is.Eq(`{"name":"app"}`, string(bs))
+}
@@ -0,0 +1,422 @@
+package json5
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gookit/config/v2"
+)
+
+// EncodeOptions for encode data to JSON5 content
+type EncodeOptions struct {
+	// Indent string for pretty output. if is empty, will output compact content.
+	Indent string
+	// UnquoteKeys don't quote the object key if it is a valid ECMAScript identifier.
+	//
+	// eg: `{name: "app"}`
+	UnquoteKeys bool
+	// SingleQuote use single quotes for strings and quoted keys.
+	SingleQuote bool
+	// TrailingComma add a trailing comma after the last element. only for pretty output.
+	TrailingComma bool
+	// HexInts encode integer numbers as hex literals. eg: 0xff
+	HexInts bool
+	// SpecialNumbers encode NaN, +Inf, -Inf as NaN, Infinity, -Infinity literals.
+	//
+	// If is false, encode these numbers will return error, like encoding/json.
+	SpecialNumbers bool
+	// LineWidth on pretty output, short object or array will be written on one line
+	// if it fits in the width. 0 means disabled.
+	LineWidth int
+}
+
+// EncodeOptFn func for set EncodeOptions
+type EncodeOptFn func(opts *EncodeOptions)
+
+// NewEncodeOptions create EncodeOptions with idiomatic JSON5 settings.
+func NewEncodeOptions(fns ...EncodeOptFn) *EncodeOptions {
+	opts := &EncodeOptions{
+		Indent:         "  ",
+		UnquoteKeys:    true,
+		TrailingComma:  true,
+		SpecialNumbers: true,
+	}
+
+	for _, fn := range fns {
+		fn(opts)
+	}
+	return opts
+}
+
+// NewEncoder create a JSON5 encoder with custom options.
+//
+// Usage:
+//
+//	enc := json5.NewEncoder(func(opts *json5.EncodeOptions) {
+//		opts.SingleQuote = true
+//	})
+func NewEncoder(fns ...EncodeOptFn) config.Encoder {
+	opts := NewEncodeOptions(fns...)
+	return func(v any) ([]byte, error) {
+		return EncodeWith(v, opts)
+	}
+}
+
+// EncodeWith encode data to JSON5 content with options.
+func EncodeWith(v any, opts *EncodeOptions) ([]byte, error) {
+	if opts == nil {
+		opts = NewEncodeOptions()
+	}
+
+	e := &encoder{opts: opts}
+	if err := e.encode(reflect.ValueOf(v), 0); err != nil {
+		return nil, err
+	}
+	return e.buf.Bytes(), nil
+}
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	jsonNumberType    = reflect.TypeOf(json.Number(""))
+)
+
+type encoder struct {
+	buf  bytes.Buffer
+	opts *EncodeOptions
+	// inline mode: write object/array on one line. eg: `{a: 1, b: [1, 2]}`
+	inline bool
+}
+
+func (e *encoder) pretty() bool { return e.opts.Indent != "" && !e.inline }
+
+// try write the container value on one line, if it fits in the LineWidth.
+func (e *encoder) tryInline(rv reflect.Value) bool {
+	if e.inline || e.opts.LineWidth <= 0 || !e.pretty() {
+		return false
+	}
+
+	sub := &encoder{opts: e.opts, inline: true}
+	if err := sub.encode(rv, 0); err != nil {
+		return false
+	}
+
+	col := e.buf.Len() - (bytes.LastIndexByte(e.buf.Bytes(), '\n') + 1)
+	if col+sub.buf.Len() > e.opts.LineWidth {
+		return false
+	}
+
+	e.buf.Write(sub.buf.Bytes())
+	return true
+}
+
+// write separator between elements
+func (e *encoder) writeComma() {
+	e.buf.WriteByte(',')
+	if e.inline {
+		e.buf.WriteByte(' ')
+	}
+}
+
+func (e *encoder) encode(rv reflect.Value, depth int) error {
+	if !rv.IsValid() {
+		e.buf.WriteString("null")
+		return nil
+	}
+
+	typ := rv.Type()
+	if typ == jsonNumberType {
+		e.buf.WriteString(rv.String())
+		return nil
+	}
+
+	// custom marshaler, struct and others: convert by encoding/json
+	if typ.Kind() != reflect.Interface && typ.Kind() != reflect.Pointer {
+		if typ.Implements(jsonMarshalerType) || typ.Implements(textMarshalerType) {
+			return e.encodeByJSON(rv, depth)
+		}
+	}
+
+	switch rv.Kind() {
+	case reflect.Interface, reflect.Pointer:
+		if rv.IsNil() {
+			e.buf.WriteString("null")
+			return nil
+		}
+		if rv.Kind() == reflect.Pointer && (typ.Implements(jsonMarshalerType) || typ.Implements(textMarshalerType)) {
+			return e.encodeByJSON(rv, depth)
+		}
+		return e.encode(rv.Elem(), depth)
+	case reflect.Bool:
+		e.buf.WriteString(strconv.FormatBool(rv.Bool()))
+	case reflect.String:
+		e.writeString(rv.String())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		e.writeInt(rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		e.writeUint(rv.Uint())
+	case reflect.Float32:
+		return e.writeFloat(rv.Float(), 32)
+	case reflect.Float64:
+		return e.writeFloat(rv.Float(), 64)
+	case reflect.Map:
+		if rv.IsNil() {
+			e.buf.WriteString("null")
+			return nil
+		}
+		return e.encodeMap(rv, depth)
+	case reflect.Slice:
+		if rv.IsNil() {
+			e.buf.WriteString("null")
+			return nil
+		}
+		// []byte is encoded as base64 string, same as encoding/json
+		if typ.Elem().Kind() == reflect.Uint8 {
+			return e.encodeByJSON(rv, depth)
+		}
+		return e.encodeList(rv, depth)
+	case reflect.Array:
+		return e.encodeList(rv, depth)
+	case reflect.Struct:
+		return e.encodeByJSON(rv, depth)
+	default:
+		return fmt.Errorf("json5: unsupported type: %s", typ)
+	}
+	return nil
+}
+
+// encodeByJSON convert value to generic data by encoding/json, then encode it.
+func (e *encoder) encodeByJSON(rv reflect.Value, depth int) error {
+	bs, err := json.Marshal(rv.Interface())
+	if err != nil {
+		return err
+	}
+
+	var data any
+	dec := json.NewDecoder(bytes.NewReader(bs))
+	dec.UseNumber()
+	if err = dec.Decode(&data); err != nil {
+		return err
+	}
+	return e.encode(reflect.ValueOf(data), depth)
+}
+
+func (e *encoder) encodeMap(rv reflect.Value, depth int) error {
+	if rv.Len() == 0 {
+		e.buf.WriteString("{}")
+		return nil
+	}
+	if e.tryInline(rv) {
+		return nil
+	}
+
+	type entry struct {
+		key string
+		val reflect.Value
+	}
+
+	entries := make([]entry, 0, rv.Len())
+	iter := rv.MapRange()
+	for iter.Next() {
+		entries = append(entries, entry{key: mapKeyString(iter.Key()), val: iter.Value()})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].key < entries[j].key })
+
+	e.buf.WriteByte('{')
+	for i, ent := range entries {
+		if i > 0 {
+			e.writeComma()
+		}
+		e.writeNewline(depth + 1)
+		e.writeKey(ent.key)
+		e.buf.WriteByte(':')
+		if e.pretty() || e.inline {
+			e.buf.WriteByte(' ')
+		}
+
+		if err := e.encode(ent.val, depth+1); err != nil {
+			return err
+		}
+	}
+
+	e.writeClose('}', depth)
+	return nil
+}
+
+func (e *encoder) encodeList(rv reflect.Value, depth int) error {
+	ln := rv.Len()
+	if ln == 0 {
+		e.buf.WriteString("[]")
+		return nil
+	}
+	if e.tryInline(rv) {
+		return nil
+	}
+
+	e.buf.WriteByte('[')
+	for i := 0; i < ln; i++ {
+		if i > 0 {
+			e.writeComma()
+		}
+		e.writeNewline(depth + 1)
+
+		if err := e.encode(rv.Index(i), depth+1); err != nil {
+			return err
+		}
+	}
+
+	e.writeClose(']', depth)
+	return nil
+}
+
+func (e *encoder) writeClose(char byte, depth int) {
+	if e.pretty() {
+		if e.opts.TrailingComma {
+			e.buf.WriteByte(',')
+		}
+		e.writeNewline(depth)
+	}
+	e.buf.WriteByte(char)
+}
+
+func (e *encoder) writeNewline(depth int) {
+	if !e.pretty() {
+		return
+	}
+
+	e.buf.WriteByte('\n')
+	for i := 0; i < depth; i++ {
+		e.buf.WriteString(e.opts.Indent)
+	}
+}
+
+func (e *encoder) writeKey(key string) {
+	if e.opts.UnquoteKeys && isIdentifier(key) {
+		e.buf.WriteString(key)
+		return
+	}
+	e.writeString(key)
+}
+
+func (e *encoder) writeInt(n int64) {
+	if !e.opts.HexInts {
+		e.buf.WriteString(strconv.FormatInt(n, 10))
+		return
+	}
+
+	if n < 0 {
+		e.buf.WriteByte('-')
+		e.writeUint(uint64(-n))
+		return
+	}
+	e.writeUint(uint64(n))
+}
+
+func (e *encoder) writeUint(n uint64) {
+	if e.opts.HexInts {
+		e.buf.WriteString("0x")
+		e.buf.WriteString(strconv.FormatUint(n, 16))
+		return
+	}
+	e.buf.WriteString(strconv.FormatUint(n, 10))
+}
+
+func (e *encoder) writeFloat(f float64, bits int) error {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		if !e.opts.SpecialNumbers {
+			return fmt.Errorf("json5: unsupported value: %s", strconv.FormatFloat(f, 'g', -1, bits))
+		}
+
+		switch {
+		case math.IsNaN(f):
+			e.buf.WriteString("NaN")
+		case f > 0:
+			e.buf.WriteString("Infinity")
+		default:
+			e.buf.WriteString("-Infinity")
+		}
+		return nil
+	}
+
+	// same format rule as encoding/json
+	format := byte('f')
+	if abs := math.Abs(f); abs != 0 {
+		if bits == 64 && (abs < 1e-6 || abs >= 1e21) || bits == 32 && (float32(abs) < 1e-6 || float32(abs) >= 1e21) {
+			format = 'e'
+		}
+	}
+	e.buf.WriteString(strconv.FormatFloat(f, format, -1, bits))
+	return nil
+}
+
+func (e *encoder) writeString(s string) {
+	quote := byte('"')
+	if e.opts.SingleQuote {
+		quote = '\''
+	}
+
+	e.buf.WriteByte(quote)
+	for _, r := range s {
+		switch r {
+		case rune(quote), '\\':
+			e.buf.WriteByte('\\')
+			e.buf.WriteRune(r)
+		case '\n':
+			e.buf.WriteString(`\n`)
+		case '\r':
+			e.buf.WriteString(`\r`)
+		case '\t':
+			e.buf.WriteString(`\t`)
+		case '\b':
+			e.buf.WriteString(`\b`)
+		case '\f':
+			e.buf.WriteString(`\f`)
+		case '\u2028', '\u2029', utf8.RuneError:
+			_, _ = fmt.Fprintf(&e.buf, `\u%04x`, r)
+		default:
+			if r < 0x20 {
+				_, _ = fmt.Fprintf(&e.buf, `\u%04x`, r)
+			} else {
+				e.buf.WriteRune(r)
+			}
+		}
+	}
+	e.buf.WriteByte(quote)
+}
+
+func mapKeyString(rv reflect.Value) string {
+	if rv.Kind() == reflect.Interface {
+		rv = rv.Elem()
+	}
+
+	if rv.Kind() == reflect.String {
+		return rv.String()
+	}
+	return fmt.Sprint(rv.Interface())
+}
+
+// isIdentifier check the key is a valid ECMAScript identifier name.
+func isIdentifier(key string) bool {
+	if key == "" {
+		return false
+	}
+
+	for i, r := range key {
+		if r == '$' || r == '_' || unicode.IsLetter(r) {
+			continue
+		}
+		if i > 0 && (unicode.IsDigit(r) || unicode.Is(unicode.Mn, r) || unicode.Is(unicode.Mc, r)) {
+			continue
+		}
+		return false
+	}
+	return true
+}
@@ -0,0 +1,169 @@
+package json5_test
+
+import (
+	"bytes"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/gookit/config/v2"
+	"github.com/gookit/config/v2/json5"
+	"github.com/gookit/goutil/testutil/assert"
+)
+
+func TestEncodeWith(t *testing.T) {
+	is := assert.New(t)
+
+	mp := map[string]any{
+		"name":   "app",
+		"zh-CN":  "it's",
+		"ports":  []int{80, 443},
+		"empty":  map[string]any{},
+		"nilVal": nil,
+	}
+
+	// compact and quoted, like JSON
+	bs, err := json5.EncodeWith(mp, &json5.EncodeOptions{})
+	is.NoErr(err)
+	is.Eq(`{"empty":{},"name":"app","nilVal":null,"ports":[80,443],"zh-CN":"it's"}`, string(bs))
+
+	// idiomatic JSON5
+	bs, err = json5.EncodeWith(mp, nil)
+	is.NoErr(err)
+	is.Eq(`{
+  empty: {},
+  name: "app",
+  nilVal: null,
+  ports: [
+    80,
+    443,
+  ],
+  "zh-CN": "it's",
+}`, string(bs))
+
+	// single quotes
+	bs, err = json5.EncodeWith(mp, json5.NewEncodeOptions(func(opts *json5.EncodeOptions) {
+		opts.Indent = ""
+		opts.SingleQuote = true
+	}))
+	is.NoErr(err)
+	is.Eq(`{empty:{},name:'app',nilVal:null,ports:[80,443],'zh-CN':'it\'s'}`, string(bs))
+}
+
+func TestEncodeWith_numbers(t *testing.T) {
+	is := assert.New(t)
+
+	mp := map[string]any{
+		"hex":  255,
+		"neg":  int8(-16),
+		"inf":  math.Inf(1),
+		"ninf": math.Inf(-1),
+		"nan":  math.NaN(),
+		"f32":  float32(1.5),
+	}
+
+	enc := json5.NewEncoder(func(opts *json5.EncodeOptions) {
+		opts.Indent = ""
+		opts.HexInts = true
+	})
+	bs, err := enc(mp)
+	is.NoErr(err)
+	is.Eq(`{f32:1.5,hex:0xff,inf:Infinity,nan:NaN,neg:-0x10,ninf:-Infinity}`, string(bs))
+
+	// disable special numbers
+	_, err = json5.EncodeWith(mp, &json5.EncodeOptions{})
+	is.ErrSubMsg(err, "unsupported value")
+
+	// unsupported type
+	_, err = json5.EncodeWith(map[string]any{"fn": func() {}}, nil)
+	is.ErrSubMsg(err, "unsupported type")
+}
+
+func TestEncodeWith_struct(t *testing.T) {
+	is := assert.New(t)
+
+	type user struct {
+		Name    string    `json:"name"`
+		Created time.Time `json:"created"`
+	}
+
+	tm := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	bs, err := json5.EncodeWith(&user{Name: "inhere", Created: tm}, json5.NewEncodeOptions(func(opts *json5.EncodeOptions) {
+		opts.Indent = ""
+	}))
+	is.NoErr(err)
+	is.Eq(`{created:"2024-01-02T03:04:05Z",name:"inhere"}`, string(bs))
+}
+
+func TestEncoder_roundTrip(t *testing.T) {
+	is := assert.New(t)
+
+	c := config.NewEmpty("test")
+	c.AddDriver(json5.Driver)
+	err := c.LoadFiles("../testdata/json_base.json5")
+	is.NoErr(err)
+
+	file := t.TempDir() + "/dump.json5"
+	err = c.DumpToFile(file, json5.Name)
+	is.NoErr(err)
+
+	c2 := config.NewEmpty("test2")
+	c2.AddDriver(json5.Driver)
+	err = c2.LoadFiles(file)
+	is.NoErr(err)
+	is.Eq(c.Data(), c2.Data())
+	is.Eq("val2", c2.String("lang.allowed.zh-CN"))
+
+	// special literals can be decoded back
+	bs, err := json5.EncodeWith(map[string]any{"max": math.Inf(1), "hex": 16}, json5.NewEncodeOptions(func(opts *json5.EncodeOptions) {
+		opts.HexInts = true
+		opts.SingleQuote = true
+	}))
+	is.NoErr(err)
+
+	data := map[string]any{}
+	err = json5.Decoder(bs, &data)
+	is.NoErr(err)
+	is.True(math.IsInf(data["max"].(float64), 1))
+	is.Eq(float64(16), data["hex"])
+}
+
+func TestDriver_EncodeWith(t *testing.T) {
+	is := assert.New(t)
+
+	c := config.NewEmpty("test")
+	c.AddDriver(json5.Driver)
+	err := c.LoadData(map[string]any{
+		"name":  "app",
+		"ports": []int{80, 443},
+		"db":    map[string]any{"host": "localhost", "port": 3306},
+	})
+	is.NoErr(err)
+
+	buf := new(bytes.Buffer)
+	_, err = c.DumpTo(buf, json5.Name, config.EncodeIndent("    "), config.EncodeLineWidth(40))
+	is.NoErr(err)
+	str := buf.String()
+	is.StrContains(str, "\n    name: \"app\",")
+	is.StrContains(str, "ports: [80, 443],")
+	is.StrContains(str, `db: {host: "localhost", port: 3306},`)
+
+	// too long for the line width
+	buf.Reset()
+	_, err = c.DumpTo(buf, json5.Name, config.EncodeIndent("    "), config.EncodeLineWidth(20))
+	is.NoErr(err)
+	is.StrContains(buf.String(), "ports: [80, 443],")
+	is.StrContains(buf.String(), "db: {\n        host:")
+
+	// empty indent: compact output
+	buf.Reset()
+	_, err = c.DumpTo(buf, json5.Name, config.EncodeSortKeys)
+	is.NoErr(err)
+	is.Eq(`{db:{host:"localhost",port:3306},name:"app",ports:[80,443]}`+"\n", buf.String())
+
+	// no options: default indent
+	buf.Reset()
+	_, err = c.DumpTo(buf, json5.Name)
+	is.NoErr(err)
+	is.StrContains(buf.String(), "\n  name: \"app\",")
+}
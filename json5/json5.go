@@ -2,8 +2,6 @@
 package json5
 
 import (
-	"encoding/json"
-
 	"github.com/gookit/config/v2"
 	"github.com/titanous/json5"
 )
@@ -14,21 +12,46 @@ const Name = "json5"
 // NAME for driver
 const NAME = Name
 
-// JSONMarshalIndent if not empty, will use json.MarshalIndent for encode data.
+// JSONMarshalIndent if not empty, will use it as indent for encode data.
+//
+// Deprecated: please use encode options. eg: c.DumpTo(w, json5.Name, config.EncodeIndent("  "))
 var JSONMarshalIndent string
 
 var (
 	// Decoder for json
 	Decoder config.Decoder = json5.Unmarshal
 
-	// Encoder for json5
+	// Encoder for json5. will output idiomatic JSON5 content, see NewEncodeOptions()
 	Encoder config.Encoder = func(v any) (out []byte, err error) {
-		if len(JSONMarshalIndent) == 0 {
-			return json.Marshal(v)
+		opts := NewEncodeOptions()
+		if len(JSONMarshalIndent) > 0 {
+			opts.Indent = JSONMarshalIndent
 		}
-		return json.MarshalIndent(v, "", JSONMarshalIndent)
+		return EncodeWith(v, opts)
 	}
 
 	// Driver for json5
-	Driver = config.NewDriver(Name, Decoder, Encoder)
+	Driver = &json5Driver{StdDriver: config.NewDriver(Name, Decoder, Encoder)}
 )
+
+type json5Driver struct {
+	*config.StdDriver
+}
+
+// EncodeWith encode data with config.EncodeOptions.
+//
+// Indent, LineWidth are supported. The nil opts will use the default indent,
+// the empty Indent will output compact content, like json.Marshal.
+// The map keys are always sorted on encode, so the SortKeys option is always honored.
+func (d *json5Driver) EncodeWith(v any, opts *config.EncodeOptions) ([]byte, error) {
+	eo := NewEncodeOptions()
+	if len(JSONMarshalIndent) > 0 {
+		eo.Indent = JSONMarshalIndent
+	}
+
+	if opts != nil {
+		eo.Indent = opts.Indent
+		eo.LineWidth = opts.LineWidth
+	}
+	return EncodeWith(v, eo)
+}
@@ -102,11 +102,15 @@ func TestEncode2JSON5(t *testing.T) {
 	}
 	bs, err := json5.Encoder(mp)
 	is.NoErr(err)
-	is.StrContains(string(bs), `"name":"app"`)
+	is.StrContains(string(bs), `  name: "app",`)
+
+	json5.JSONMarshalIndent = "    "
+	defer func() {
+		json5.JSONMarshalIndent = ""
+	}()
 
-	json5.JSONMarshalIndent = "  "
 	bs, err = json5.Encoder(mp)
 	is.NoErr(err)
 	s := string(bs)
-	is.StrContains(s, `  "name": "app"`)
+	is.StrContains(s, `    name: "app",`)
 }
@@ -0,0 +1,172 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// KeyMatch mode for matching the config keys, see Options.KeyMatch
+type KeyMatch uint8
+
+// There are supported key match modes
+const (
+	// KeyMatchExact the keys must be exactly equal. it is default.
+	KeyMatchExact KeyMatch = iota
+	// KeyMatchIgnoreCase the keys are case-insensitive. eg: "DB_HOST" == "db_host"
+	KeyMatchIgnoreCase
+	// KeyMatchIgnoreStyle the keys are case and style insensitive,
+	// snake, kebab and camel keys are equivalent. eg: "DB_HOST" == "dbHost" == "db-host"
+	KeyMatchIgnoreStyle
+)
+
+// Normalize the key by the match mode. the config keys will be saved as the normalized key.
+//
+// Example:
+//
+//	KeyMatchIgnoreCase.Normalize("DB_HOST") // "db_host"
+//	KeyMatchIgnoreStyle.Normalize("dbHost") // "db_host"
+func (km KeyMatch) Normalize(key string) string {
+	switch km {
+	case KeyMatchIgnoreCase:
+		return strings.ToLower(key)
+	case KeyMatchIgnoreStyle:
+		// keep the slice index. eg: "-1"
+		if _, err := strconv.Atoi(key); err == nil {
+			return key
+		}
+		return KeyStyleSnake.Format(key)
+	}
+	return key
+}
+
+// normalize each key of the key path by Options.KeyMatch
+func (c *Config) normKeys(keys []string) []string {
+	if km := c.opts.KeyMatch; km != KeyMatchExact {
+		for i, key := range keys {
+			keys[i] = km.Normalize(key)
+		}
+	}
+	return keys
+}
+
+// normalize the keys of the data by Options.KeyMatch. it returns a new data, the source data is not changed.
+//
+// If multi keys are normalized to the same key, will keep the value of the first key in sorted order,
+// and return a *KeyConflictError.
+func (c *Config) normData(data map[string]any) (map[string]any, error) {
+	km := c.opts.KeyMatch
+	if km == KeyMatchExact || data == nil {
+		return data, nil
+	}
+
+	kn := &keyNormalizer{km: km, sep: c.opts.Delimiter}
+	val := kn.normalize(data, "")
+	return val.(map[string]any), kn.err
+}
+
+// normalize the value for the Options.KeyMatch, the value can be map, slice or other.
+func (c *Config) normValue(val any) (any, error) {
+	if c.opts.KeyMatch == KeyMatchExact {
+		return val, nil
+	}
+
+	kn := &keyNormalizer{km: c.opts.KeyMatch, sep: c.opts.Delimiter}
+	val = kn.normalize(val, "")
+	return val, kn.err
+}
+
+type keyNormalizer struct {
+	km  KeyMatch
+	sep byte
+	// the first conflict error
+	err error
+}
+
+func (kn *keyNormalizer) normalize(val any, path string) any {
+	switch tv := val.(type) {
+	case map[string]any:
+		keys := sortedKeys(tv)
+		newMp := make(map[string]any, len(tv))
+		srcKeys := make(map[string]string, len(tv))
+		for _, key := range keys {
+			nk := kn.km.Normalize(key)
+			if !kn.checkConflict(srcKeys, key, nk, path) {
+				continue
+			}
+			newMp[nk] = kn.normalize(tv[key], joinPath(path, nk, kn.sep))
+		}
+		return newMp
+	case []any:
+		list := make([]any, len(tv))
+		for i, v := range tv {
+			list[i] = kn.normalize(v, joinPath(path, strconv.Itoa(i), kn.sep))
+		}
+		return list
+	}
+
+	// other maps. eg: map[any]any, map[string]string
+	rv := reflect.ValueOf(val)
+	if rv.Kind() != reflect.Map {
+		return val
+	}
+
+	keyKind := rv.Type().Key().Kind()
+	if keyKind != reflect.String && keyKind != reflect.Interface {
+		return val
+	}
+
+	mks := rv.MapKeys()
+	sort.Slice(mks, func(i, j int) bool {
+		return fmt.Sprint(mks[i].Interface()) < fmt.Sprint(mks[j].Interface())
+	})
+
+	elemType := rv.Type().Elem()
+	newMp := reflect.MakeMapWithSize(rv.Type(), len(mks))
+	srcKeys := make(map[string]string, len(mks))
+	for _, mk := range mks {
+		key, ok := mk.Interface().(string)
+		if !ok {
+			newMp.SetMapIndex(mk, rv.MapIndex(mk))
+			continue
+		}
+
+		nk := kn.km.Normalize(key)
+		if !kn.checkConflict(srcKeys, key, nk, path) {
+			continue
+		}
+
+		elem := kn.normalize(rv.MapIndex(mk).Interface(), joinPath(path, nk, kn.sep))
+		newMp.SetMapIndex(reflect.ValueOf(nk).Convert(rv.Type().Key()), toReflectValue(elem, elemType))
+	}
+	return newMp.Interface()
+}
+
+// check the normalized key is conflict with other key. returns false on conflict.
+func (kn *keyNormalizer) checkConflict(srcKeys map[string]string, key, nk, path string) bool {
+	if first, ok := srcKeys[nk]; ok {
+		if kn.err == nil {
+			kn.err = &KeyConflictError{Path: path, Key: nk, Keys: []string{first, key}}
+		}
+		return false
+	}
+
+	srcKeys[nk] = key
+	return true
+}
+
+func sortedKeys(mp map[string]any) []string {
+	keys := make([]string, 0, len(mp))
+	for key := range mp {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// match the map key and struct field name by the mode. use for mapstructure.DecoderConfig.MatchName
+func (km KeyMatch) matchName(mapKey, fieldName string) bool {
+	return km.Normalize(mapKey) == km.Normalize(fieldName)
+}
@@ -0,0 +1,115 @@
+package config
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gookit/goutil/testutil/assert"
+)
+
+func TestKeyMatch_Normalize(t *testing.T) {
+	is := assert.New(t)
+
+	is.Eq("DB_HOST", KeyMatchExact.Normalize("DB_HOST"))
+	is.Eq("db_host", KeyMatchIgnoreCase.Normalize("DB_HOST"))
+	is.Eq("dbhost", KeyMatchIgnoreCase.Normalize("dbHost"))
+
+	for _, key := range []string{"DB_HOST", "dbHost", "db-host", "DbHost", "db_host"} {
+		is.Eq("db_host", KeyMatchIgnoreStyle.Normalize(key), key)
+	}
+	is.Eq("-1", KeyMatchIgnoreStyle.Normalize("-1"))
+	is.Eq("http_server", KeyMatchIgnoreStyle.Normalize("HTTPServer"))
+}
+
+func TestConfig_KeyMatch(t *testing.T) {
+	is := assert.New(t)
+	c := New("test", WithKeyMatch(KeyMatchIgnoreStyle))
+
+	err := c.LoadStrings(JSON, `{
+		"DB_HOST": "localhost",
+		"dbPort": 3306,
+		"app-info": {"appName": "demo", "Tags": ["a", "b"], "servers": [{"Host-Name": "s1"}]}
+	}`)
+	is.NoErr(err)
+
+	// saved as the normalized keys
+	is.Contains(c.Data(), "db_host")
+	is.Contains(c.Data(), "app_info")
+
+	// get
+	is.Eq("localhost", c.String("db-host"))
+	is.Eq("localhost", c.String("dbHost"))
+	is.Eq(3306, c.Int("DB_PORT"))
+	is.Eq("demo", c.String("appInfo.APP_NAME"))
+	is.Eq("b", c.String("APP_INFO.tags[-1]"))
+	is.Eq("s1", c.String("app-info.servers.0.hostName"))
+	is.True(c.Exists("app_info.app-name"))
+	is.False(c.Exists("app_info.appname"))
+	is.Eq("demo", c.Value("AppInfo").Get("app-name").String())
+
+	rs, err := c.Query("appInfo.servers[?HostName==s1].host-name")
+	is.NoErr(err)
+	is.Eq([]QueryResult{{"app_info.servers.0.host_name", "s1"}}, rs)
+
+	// set
+	is.NoErr(c.Set("app-info.LOG_LEVEL", "debug"))
+	is.Eq("debug", c.String("appInfo.logLevel"))
+	is.NoErr(c.Set("newMap", map[string]any{"SubKey": 1}))
+	is.Eq(1, c.Int("new_map.sub-key"))
+
+	// load and merge
+	is.NoErr(c.LoadData(map[string]any{"dbHost": "127.0.0.1"}, map[string]string{"App-Env": "dev"}))
+	is.Eq("127.0.0.1", c.String("DB_HOST"))
+	is.Eq("dev", c.String("appEnv"))
+
+	// structure
+	type AppInfo struct {
+		AppName  string
+		LogLevel string `mapstructure:"log-level"`
+		Tags     []string
+	}
+	info := &AppInfo{}
+	is.NoErr(c.Structure("app-info", info))
+	is.Eq("demo", info.AppName)
+	is.Eq("debug", info.LogLevel)
+	is.Eq([]string{"a", "b"}, info.Tags)
+
+	// ignore case
+	c = New("test", WithKeyMatch(KeyMatchIgnoreCase))
+	is.NoErr(c.LoadStrings(JSON, `{"DB": {"Host": "localhost"}}`))
+	is.Eq("localhost", c.String("db.HOST"))
+	is.False(c.Exists("db_host"))
+}
+
+func TestConfig_KeyMatch_conflict(t *testing.T) {
+	is := assert.New(t)
+	c := New("test", WithKeyMatch(KeyMatchIgnoreStyle))
+
+	err := c.LoadStrings(JSON, `{"db": {"db_host": "a", "dbHost": "b"}}`)
+	is.Err(err)
+
+	var kce *KeyConflictError
+	is.True(errors.As(err, &kce))
+	is.Eq("db", kce.Path)
+	is.Eq("db_host", kce.Key)
+	is.Eq([]string{"dbHost", "db_host"}, kce.Keys)
+	is.ErrMsg(err, `config: the keys ["dbHost" "db_host"] are conflicting, all of them match the key 'db_host' in 'db'`)
+	is.Empty(c.Data())
+
+	err = c.Set("db", map[string]any{"Host": 1, "host": 2})
+	is.ErrSubMsg(err, "are conflicting")
+	err = c.LoadData(map[string]string{"APP_NAME": "a", "appName": "b"})
+	is.ErrSubMsg(err, "are conflicting")
+
+	// keep the first key in sorted order
+	c.SetData(map[string]any{"Name": "a", "name": "b"})
+	is.ErrSubMsg(c.Error(), "are conflicting")
+	is.Eq("a", c.String("name"))
+
+	// exact mode is not normalized
+	c = New("test")
+	is.NoErr(c.LoadStrings(JSON, `{"db_host": "a", "dbHost": "b"}`))
+	is.Eq("a", c.String("db_host"))
+	is.Eq("b", c.String("dbHost"))
+	is.False(c.Exists("db-host"))
+}
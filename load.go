@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"io/fs"
+	"maps"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -15,6 +16,7 @@ import (
 	"dario.cat/mergo"
 	"github.com/gookit/goutil/errorx"
 	"github.com/gookit/goutil/fsutil"
+	"github.com/gookit/goutil/maputil"
 	"github.com/gookit/goutil/strutil"
 )
 
@@ -102,12 +104,13 @@ func (c *Config) LoadRemote(format, url string) (err error) {
 		return fmt.Errorf("fetch remote config error, reply status code is %d", resp.StatusCode)
 	}
 
-	// read response content
-	bts, err := io.ReadAll(resp.Body)
-	if err == nil {
-		if err = c.parseSourceCode(format, bts); err != nil {
-			return
-		}
+	// read and parse response content
+	data, err := c.parseSourceReader(format, resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if err = c.loadDataMap(data); err == nil {
 		c.loadedUrls = append(c.loadedUrls, url)
 	}
 	return
@@ -256,19 +259,77 @@ func LoadData(dataSource ...any) error { return dc.LoadData(dataSource...) }
 //   - map[string]any
 //   - map[string]string
 func (c *Config) LoadData(dataSources ...any) (err error) {
+	if c.root != nil {
+		for i, ds := range dataSources {
+			if smp, ok := ds.(map[string]string); ok {
+				ds = maputil.ToAnyMap(smp)
+			}
+			dataSources[i] = c.wrapScopeData(ds)
+		}
+		return c.root.LoadData(dataSources...)
+	}
+
 	if c.opts.Delimiter == 0 {
 		c.opts.Delimiter = defaultDelimiter
 	}
 
 	var loaded bool
+	var found []*keyAlias
+	c.lock.Lock()
+	defer func() {
+		c.lock.Unlock()
+		if err == nil {
+			c.warnDeprecated(found)
+		}
+		c.flushEvents()
+	}()
+
+	// merge to a new data(copy on write), publish it after all data sources are merged.
+	old := c.getData()
+	data := old
 	for _, ds := range dataSources {
 		if smp, ok := ds.(map[string]string); ok {
-			loaded = true
-			c.LoadSMap(smp)
-			continue
+			if c.opts.ExpandKeys {
+				ds = maputil.ToAnyMap(smp)
+			} else {
+				if _, err = c.normValue(smp); err != nil {
+					return err
+				}
+
+				loaded = true
+				if data = maps.Clone(data); data == nil {
+					data = make(map[string]any, len(smp))
+				}
+				c.loadSMap(data, smp)
+				continue
+			}
+		}
+
+		if mp, ok := ds.(map[string]any); ok && c.opts.ExpandKeys {
+			if ds, err = Unflatten(mp, c.opts.Delimiter); err != nil {
+				return err
+			}
+		}
+
+		if ds, err = c.normValue(ds); err != nil {
+			return err
+		}
+
+		// copy the data for migrate alias keys, don't change the source data
+		if mp, ok := ds.(map[string]any); ok && len(c.aliases()) > 0 {
+			mp = deepCopy(mp).(map[string]any)
+			found = append(found, c.migrateAliases(mp)...)
+			ds = mp
+		}
+
+		if mp, ok := ds.(map[string]any); ok {
+			data, err = c.mergeData(data, mp)
+		} else {
+			// the mergo changes the dst, merge to a copy
+			data = deepCopy(data).(map[string]any)
+			err = mergo.Merge(&data, ds, c.opts.MergeOptions...)
 		}
 
-		err = mergo.Merge(&c.data, ds, c.opts.MergeOptions...)
 		if err != nil {
 			return errorx.WithStack(err)
 		}
@@ -276,17 +337,46 @@ func (c *Config) LoadData(dataSources ...any) (err error) {
 	}
 
 	if loaded {
-		c.fireHook(OnLoadData)
+		c.storeData(data)
+		c.queueEvent(OnLoadData, nil, c.collectChanges(OnLoadData, nil, old, true, data, true))
 	}
 	return
 }
 
 // LoadSMap to config
 func (c *Config) LoadSMap(smp map[string]string) {
+	if c.root != nil {
+		c.addError(c.root.LoadData(c.wrapScopeData(maputil.ToAnyMap(smp))))
+		return
+	}
+
+	if c.opts.ExpandKeys {
+		if err := c.LoadData(maputil.ToAnyMap(smp)); err != nil {
+			c.addError(err)
+		}
+		return
+	}
+
+	c.lock.Lock()
+	old := c.getData()
+	data := maps.Clone(old)
+	if data == nil {
+		data = make(map[string]any, len(smp))
+	}
+
+	c.loadSMap(data, smp)
+	c.storeData(data)
+	c.queueEvent(OnLoadData, nil, c.collectChanges(OnLoadData, nil, old, true, data, true))
+	c.lock.Unlock()
+
+	c.flushEvents()
+}
+
+// load the string map to the data, the keys are normalized by Options.KeyMatch
+func (c *Config) loadSMap(data map[string]any, smp map[string]string) {
 	for k, v := range smp {
-		c.data[k] = v
+		data[c.opts.KeyMatch.Normalize(k)] = v
 	}
-	c.fireHook(OnLoadData)
 }
 
 // LoadSources load one or multi byte data
@@ -340,6 +430,40 @@ func (c *Config) LoadStrings(format string, str string, more ...string) (err err
 	return
 }
 
+// LoadDocuments load multi documents content, returns one config instance per document.
+func LoadDocuments(format string, src []byte) ([]*Config, error) {
+	return dc.LoadDocuments(format, src)
+}
+
+// LoadDocuments parse multi documents content(eg: YAML with `---`), returns one new config
+// instance per document. the data of current config will not be changed.
+//
+// The new instances are named "NAME.INDEX", and use the same options and drivers as current config.
+// The hooks are not copied, so they are not fired on load the documents.
+//
+// Usage:
+//
+//	cs, err := c.LoadDocuments(config.Yaml, content)
+//	cs[1].String("name")
+func (c *Config) LoadDocuments(format string, src []byte) ([]*Config, error) {
+	docs, err := c.parseSourceDocs(format, src)
+	if err != nil {
+		return nil, err
+	}
+
+	cs := make([]*Config, 0, len(docs))
+	for i, doc := range docs {
+		nc := c.newChild(fmt.Sprintf("%s.%d", c.name, i))
+		// don't fire the hooks of the parent on load each document
+		nc.opts.HookFunc, nc.opts.listeners = nil, nil
+		if err = nc.loadDataMap(doc); err != nil {
+			return nil, err
+		}
+		cs = append(cs, nc)
+	}
+	return cs, nil
+}
+
 // LoadFilesByFormat load one or multi config files by give format, will fire OnLoadData event
 func LoadFilesByFormat(format string, configFiles ...string) error {
 	return dc.LoadFilesByFormat(format, configFiles...)
@@ -459,37 +583,66 @@ func (c *Config) LoadFromDir(dirPath, format string, loFns ...LoadOptFn) (err er
 func ReloadFiles() error { return dc.ReloadFiles() }
 
 // ReloadFiles reload config data use loaded files. use on watching loaded files change
+//
+// The files are loaded to a temporary config, then the new data is published at once.
+// So the readers will see the old or new data, and the data is not changed on error.
 func (c *Config) ReloadFiles() (err error) {
 	files := c.loadedFiles
 	if len(files) == 0 {
 		return
 	}
 
-	var data map[string]any
-	c.reloading = true
-	c.ClearCaches()
+	root := c
+	if c.root != nil {
+		root = c.root
+	}
 
-	defer func() {
-		// revert to back up data on error
-		if err != nil {
-			c.data = data
-		}
+	// load files to a temporary config. the hooks are not copied.
+	tmp := root.newChild(root.name)
+	tmp.opts.HookFunc, tmp.opts.listeners = nil, nil
+	root.lock.RLock()
+	tmp.warnedKeys = maps.Clone(root.warnedKeys)
+	root.lock.RUnlock()
 
-		c.lock.Unlock()
-		c.reloading = false
+	loader := tmp
+	if c.root != nil {
+		loader = tmp.Scope(c.scopePath)
+	}
+	if err = loader.LoadFiles(files...); err != nil {
+		return err
+	}
 
-		if err == nil {
-			c.fireHook(OnReloadData)
+	root.lock.Lock()
+	old, oldData := c.rawData(), root.getData()
+	if c.root == nil {
+		root.storeData(tmp.getData())
+	} else {
+		// the scope view: replace the sub data
+		sub := loader.rawData()
+		if sub == nil {
+			sub = make(map[string]any)
 		}
-	}()
+		err = root.setLocked(c.scopePath, sub, true, "")
+	}
 
-	// with lock
-	c.lock.Lock()
-	data = c.data
-	c.data = make(map[string]any)
+	if err == nil {
+		for key := range tmp.warnedKeys {
+			if root.warnedKeys == nil {
+				root.warnedKeys = make(map[string]bool)
+			}
+			root.warnedKeys[key] = true
+		}
+		c.addComments(loader.comments)
 
-	// reload config files
-	return c.LoadFiles(files...)
+		root.pushHistory(oldData, root.version.Load())
+		root.ClearCaches()
+		root.queueEvent(OnReloadData, c.prefix, root.collectChanges(OnReloadData, c.prefix, old, true, c.rawData(), true))
+	}
+	root.lock.Unlock()
+
+	// fire events after the lock is released
+	root.flushEvents()
+	return err
 }
 
 // load config file, will fire OnLoadData event
@@ -506,23 +659,22 @@ func (c *Config) loadFile(file string, loadExist bool, format string) (err error
 	//noinspection GoUnhandledErrorResult
 	defer fd.Close()
 
-	// read file content
-	bts, err := io.ReadAll(fd)
-	if err == nil {
-		// get format for file ext
-		if format == "" {
-			format = strings.Trim(filepath.Ext(file), ".")
-		}
+	// get format for file ext. if is empty, will detect by content
+	if format == "" {
+		format = strings.Trim(filepath.Ext(file), ".")
+	}
 
-		// parse file content
-		if err = c.parseSourceCode(format, bts); err != nil {
-			return
-		}
+	// read and parse file content
+	data, err := c.parseSourceReader(format, fd)
+	if err != nil {
+		return err
+	}
 
-		if !c.reloading {
-			c.loadedFiles = append(c.loadedFiles, file)
-		}
+	if err = c.loadDataMap(data); err != nil {
+		return err
 	}
+
+	c.loadedFiles = append(c.loadedFiles, file)
 	return
 }
 
@@ -537,30 +689,74 @@ func (c *Config) parseSourceCode(format string, blob []byte) (err error) {
 }
 
 func (c *Config) loadDataMap(data map[string]any) (err error) {
+	if c.root != nil {
+		return c.root.loadDataMap(c.wrapScopeData(data))
+	}
+
+	if data, err = c.normData(data); err != nil {
+		return err
+	}
+	c.lock.Lock()
+	found := c.migrateAliases(data)
+
 	// first: init config data
-	if len(c.data) == 0 {
-		c.data = data
+	old := c.getData()
+	if len(old) == 0 {
+		data = c.stripMergeDirective(data)
 	} else {
-		// again ... will merge data
-		err = mergo.Merge(&c.data, data, c.opts.MergeOptions...)
+		// again ... will merge data to a new data(copy on write), the published data is not changed.
+		data, err = c.mergeData(old, data)
 	}
 
-	if !c.reloading && err == nil {
-		c.fireHook(OnLoadData)
+	if err == nil {
+		c.storeData(data)
+		c.queueEvent(OnLoadData, nil, c.collectChanges(OnLoadData, nil, old, true, data, true))
+	}
+	c.lock.Unlock()
+
+	if err == nil {
+		c.warnDeprecated(found)
 	}
+	c.flushEvents()
 	return err
 }
 
+// parse config source from a reader. will use StreamDecoder if the driver supported.
+func (c *Config) parseSourceReader(format string, r io.Reader) (map[string]any, error) {
+	if format = c.resolveFormat(format); format != "" {
+		if d, ok := c.getDriver(format); ok {
+			sd, ok := d.(StreamDecoder)
+			if ok && !isMultiDocsOrComments(d) {
+				c.initDelimiter()
+				data := make(map[string]any)
+				return data, sd.DecodeReader(r, &data)
+			}
+		}
+	}
+
+	bts, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return c.parseSourceToMap(format, bts)
+}
+
 // parse config source code to Config.
 func (c *Config) parseSourceToMap(format string, blob []byte) (map[string]any, error) {
-	format = c.resolveFormat(format)
-	decode := c.decoders[format]
-	if decode == nil {
-		return nil, errors.New("not register decoder for the format: " + format)
+	if format = c.resolveFormat(format); format == "" {
+		if format = c.sniffFormat(blob); format == "" {
+			return nil, errors.New("cannot detect the format of the config content")
+		}
 	}
 
-	if c.opts.Delimiter == 0 {
-		c.opts.Delimiter = defaultDelimiter
+	c.initDelimiter()
+	if d, ok := c.getDriver(format); ok {
+		return c.decodeByDriver(d, blob)
+	}
+
+	decode := c.getDecoder(format)
+	if decode == nil {
+		return nil, errors.New("not register decoder for the format: " + format)
 	}
 
 	// decode content to data
@@ -571,3 +767,99 @@ func (c *Config) parseSourceToMap(format string, blob []byte) (map[string]any, e
 	}
 	return data, nil
 }
+
+// parse config source to documents. if the driver is not a MultiDocsDecoder, will return one document.
+func (c *Config) parseSourceDocs(format string, blob []byte) ([]map[string]any, error) {
+	if format = c.resolveFormat(format); format == "" {
+		format = c.sniffFormat(blob)
+	}
+
+	if d, ok := c.getDriver(format); ok {
+		if md, ok := d.(MultiDocsDecoder); ok {
+			c.initDelimiter()
+			return md.DecodeDocs(blob)
+		}
+	}
+
+	data, err := c.parseSourceToMap(format, blob)
+	if err != nil {
+		return nil, err
+	}
+	return []map[string]any{data}, nil
+}
+
+// decode content by driver, will check the optional interfaces of the driver.
+func (c *Config) decodeByDriver(d DriverV2, blob []byte) (map[string]any, error) {
+	if md, ok := d.(MultiDocsDecoder); ok {
+		docs, err := md.DecodeDocs(blob)
+		if err != nil {
+			return nil, err
+		}
+		return c.mergeDocs(docs)
+	}
+
+	data := make(map[string]any)
+	if cd, ok := d.(CommentsDecoder); ok {
+		comments, err := cd.DecodeWithComments(blob, &data)
+		if err != nil {
+			return nil, err
+		}
+
+		c.addComments(comments)
+		return data, nil
+	}
+
+	if err := d.Decode(blob, &data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// merge multi documents data in order. if Options.DocumentsKey is set, will save documents as a list.
+func (c *Config) mergeDocs(docs []map[string]any) (map[string]any, error) {
+	if c.opts.DocumentsKey != "" {
+		list := make([]any, len(docs))
+		for i, doc := range docs {
+			list[i] = doc
+		}
+		return map[string]any{c.opts.DocumentsKey: list}, nil
+	}
+
+	data := make(map[string]any)
+	for _, doc := range docs {
+		var err error
+		if data, err = c.mergeData(data, doc); err != nil {
+			return nil, errorx.WithStack(err)
+		}
+	}
+	return data, nil
+}
+
+func (c *Config) addComments(comments map[string]string) {
+	if len(comments) == 0 {
+		return
+	}
+
+	c.commentLock.Lock()
+	defer c.commentLock.Unlock()
+	if c.comments == nil {
+		c.comments = make(map[string]string, len(comments))
+	}
+	for k, v := range comments {
+		c.comments[k] = v
+	}
+}
+
+func (c *Config) initDelimiter() {
+	if c.opts.Delimiter == 0 {
+		c.opts.Delimiter = defaultDelimiter
+	}
+}
+
+func isMultiDocsOrComments(d DriverV2) bool {
+	if _, ok := d.(MultiDocsDecoder); ok {
+		return true
+	}
+	_, ok := d.(CommentsDecoder)
+	return ok
+}
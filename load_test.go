@@ -1,11 +1,13 @@
 package config
 
 import (
+	"bytes"
 	"flag"
 	"os"
 	"reflect"
 	"runtime"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/gookit/goutil/dump"
@@ -390,3 +392,100 @@ func TestReloadFilesResetRemovedKeys(t *testing.T) {
 	assert.Eq(t, "app2", c.String("name"))
 	assert.False(t, c.Exists("debug"))
 }
+
+func TestReloadFiles_atomic(t *testing.T) {
+	is := assert.New(t)
+	file := t.TempDir() + "/config.json"
+	contents := []string{`{"db": {"host": "a", "port": 1}}`, `{"db": {"host": "b", "port": 2}}`}
+	is.NoErr(os.WriteFile(file, []byte(contents[0]), 0644))
+
+	c := New("reload-atomic")
+	is.NoErr(c.LoadFiles(file))
+
+	// the readers see the old or new data, never the partial data
+	var wg sync.WaitGroup
+	done := make(chan struct{})
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+
+				db := c.Sub("db")
+				if (db["host"] == "a") != (db["port"] == float64(1)) || db["host"] == nil {
+					t.Errorf("read the partial data: %v", db)
+					return
+				}
+			}
+		}()
+	}
+
+	for i := 1; i <= 20; i++ {
+		if err := os.WriteFile(file, []byte(contents[i%2]), 0644); err != nil {
+			t.Error(err)
+		}
+		is.NoErr(c.ReloadFiles())
+	}
+	close(done)
+	wg.Wait()
+	is.Eq("a", c.String("db.host"))
+
+	// the data is not changed on reload error
+	is.NoErr(os.WriteFile(file, []byte(`{"db": `), 0644))
+	is.Err(c.ReloadFiles())
+	is.Eq("a", c.String("db.host"))
+	is.Eq(1, c.Int("db.port"))
+}
+
+func TestConfig_LoadDocuments(t *testing.T) {
+	is := assert.New(t)
+	src := []byte(`{"name": "app", "age": 2}
+---
+{"name": "app2"}`)
+
+	c := NewEmpty("test", ParseEnv)
+	c.AddDriver(&docsDriver{StdDriver: NewDriver("test", JSONDecoder, JSONEncoder)})
+
+	cs, err := c.LoadDocuments("test", src)
+	is.NoErr(err)
+	is.Len(cs, 2)
+	is.Eq("test.0", cs[0].Name())
+	is.Eq("app", cs[0].String("name"))
+	is.Eq("app2", cs[1].String("name"))
+	is.False(cs[1].Exists("age"))
+	is.True(cs[1].Options().ParseEnv)
+	is.Empty(c.Data())
+
+	// dump documents
+	buf := new(bytes.Buffer)
+	_, err = DumpDocuments(buf, "test", cs...)
+	is.NoErr(err)
+	is.Eq(`{"age":2,"name":"app"}`+"\n---\n"+`{"name":"app2"}`, buf.String())
+
+	_, err = DumpDocuments(buf, JSON, cs...)
+	is.ErrSubMsg(err, "not exists/register driver")
+
+	// not a multi documents driver
+	c = New("json")
+	cs, err = c.LoadDocuments(JSON, []byte(`{"name": "app"}`))
+	is.NoErr(err)
+	is.Len(cs, 1)
+	is.Eq("app", cs[0].String("name"))
+
+	_, err = DumpDocuments(buf, JSON, cs...)
+	is.ErrSubMsg(err, "does not support encode multi documents")
+
+	// option: DocumentsKey
+	c = NewEmpty("test", func(opts *Options) {
+		opts.DocumentsKey = "docs"
+	})
+	c.AddDriver(&docsDriver{StdDriver: NewDriver("test", JSONDecoder, JSONEncoder)})
+	is.NoErr(c.LoadSources("test", src))
+	is.Eq("app2", c.String("docs.1.name"))
+	is.Eq(2, c.Int("docs.0.age"))
+}
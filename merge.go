@@ -0,0 +1,372 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"dario.cat/mergo"
+)
+
+// MergeStrategy the strategy for merge the value of a key on load new data.
+type MergeStrategy string
+
+// There are supported merge strategies
+const (
+	// MergeDeep the default strategy. maps are deep merged, other values are merged by Options.MergeOptions
+	MergeDeep MergeStrategy = "deep"
+	// MergeReplace replace the value wholesale
+	MergeReplace MergeStrategy = "replace"
+	// MergeAppend append the new list to the old list
+	MergeAppend MergeStrategy = "append"
+	// MergeByID merge the list of maps by the identity field, see MergeRule.IDKey
+	MergeByID MergeStrategy = "by-id"
+)
+
+// DefaultMergeIDKey the default identity field for MergeByID
+const DefaultMergeIDKey = "id"
+
+// MergeRule the merge rule of a key path. see Options.MergeRules
+type MergeRule struct {
+	Strategy MergeStrategy
+	// IDKey the identity field of the list elements for MergeByID. default is DefaultMergeIDKey
+	IDKey string
+}
+
+// ParseMergeRule parse the merge rule string. format: "STRATEGY[:ID_KEY]"
+//
+// Example:
+//
+//	ParseMergeRule("append")
+//	ParseMergeRule("by-id:name")
+func ParseMergeRule(s string) (MergeRule, error) {
+	name, idKey, _ := strings.Cut(strings.TrimSpace(s), ":")
+	rule := MergeRule{Strategy: MergeStrategy(name), IDKey: strings.TrimSpace(idKey)}
+
+	switch rule.Strategy {
+	case MergeDeep, MergeReplace, MergeAppend:
+		if rule.IDKey != "" {
+			return rule, fmt.Errorf("config: the merge strategy %q does not support the id key", name)
+		}
+	case MergeByID:
+		if rule.IDKey == "" {
+			rule.IDKey = DefaultMergeIDKey
+		}
+	default:
+		return rule, fmt.Errorf("config: invalid merge strategy %q", name)
+	}
+	return rule, nil
+}
+
+// WithMergeRule set the merge strategy for the key path. the path supports the wildcard "*". eg: "apps.*.plugins"
+//
+// Usage:
+//
+//	config.WithMergeRule("servers", config.MergeAppend)
+//	config.WithMergeRule("plugins", config.MergeByID, "name")
+func WithMergeRule(path string, strategy MergeStrategy, idKey ...string) func(*Options) {
+	return func(opts *Options) {
+		if opts.MergeRules == nil {
+			opts.MergeRules = make(map[string]MergeRule)
+		}
+
+		rule := MergeRule{Strategy: strategy}
+		if len(idKey) > 0 {
+			rule.IDKey = idKey[0]
+		}
+		opts.MergeRules[path] = rule
+	}
+}
+
+// MergeNullDelete set an explicit null value in the new data will delete the key. see Options.MergeNullDelete
+func MergeNullDelete(opts *Options) { opts.MergeNullDelete = true }
+
+// WithMergeDirective set the reserved key for declare merge strategies in the config content. see Options.MergeDirective
+func WithMergeDirective(key string) func(*Options) {
+	return func(opts *Options) {
+		opts.MergeDirective = key
+	}
+}
+
+type mergeRule struct {
+	MergeRule
+	keys []string
+}
+
+// merger merge the new data to the old data by the merge rules.
+type merger struct {
+	c     *Config
+	rules []mergeRule
+	// the directive key, empty is disabled
+	directive string
+}
+
+func (c *Config) newMerger() (*merger, error) {
+	m := &merger{c: c, directive: c.opts.MergeDirective}
+	for path, rule := range c.opts.MergeRules {
+		keys, err := c.parseKeys(path)
+		if err != nil {
+			return nil, err
+		}
+
+		if rule.Strategy == MergeByID && rule.IDKey == "" {
+			rule.IDKey = DefaultMergeIDKey
+		}
+		m.rules = append(m.rules, mergeRule{MergeRule: rule, keys: keys})
+	}
+	return m, nil
+}
+
+// merge the src data to the dst data, returns the merged data. see Options.MergeRules
+//
+// The dst data is not changed, only the changed maps are copied, the others are shared with the dst.
+// An explicit nil value in the src will delete the key from the dst if Options.MergeNullDelete is true,
+// otherwise the old value is kept.
+func (c *Config) mergeData(dst, src map[string]any) (map[string]any, error) {
+	m, err := c.newMerger()
+	if err != nil {
+		return nil, err
+	}
+	return m.mergeMap(dst, src, nil)
+}
+
+// remove the directive keys from the data, use for the first loaded data. the data is not changed.
+func (c *Config) stripMergeDirective(data map[string]any) map[string]any {
+	if c.opts.MergeDirective == "" {
+		return data
+	}
+	return (&merger{directive: c.opts.MergeDirective}).strip(data).(map[string]any)
+}
+
+func (m *merger) mergeMap(dst, src map[string]any, path []string) (map[string]any, error) {
+	if err := m.collectDirective(src, path); err != nil {
+		return nil, err
+	}
+
+	// copy on write, the dst may be the published data
+	if dst = maps.Clone(dst); dst == nil {
+		dst = make(map[string]any, len(src))
+	}
+
+	for _, k := range sortedKeys(src) {
+		if m.directive != "" && k == m.directive {
+			continue
+		}
+
+		sv := src[k]
+		keys := append(path[:len(path):len(path)], k)
+
+		dv, ok := dst[k]
+		if !ok {
+			dst[k] = m.strip(sv)
+			continue
+		}
+
+		// explicit null: delete the key or keep the old value
+		if sv == nil {
+			if m.c.opts.MergeNullDelete {
+				delete(dst, k)
+			}
+			continue
+		}
+
+		rule, err := m.ruleOf(keys, sv)
+		if err != nil {
+			return nil, err
+		}
+
+		switch rule.Strategy {
+		case MergeReplace:
+			dst[k] = m.strip(sv)
+		case MergeAppend:
+			dst[k] = appendList(dv, m.strip(sv))
+		case MergeByID:
+			if dst[k], err = m.mergeByID(dv, sv, keys, rule.IDKey); err != nil {
+				return nil, err
+			}
+		default:
+			dm, ok1 := dv.(map[string]any)
+			sm, ok2 := sv.(map[string]any)
+			if ok1 && ok2 {
+				if dst[k], err = m.mergeMap(dm, sm, keys); err != nil {
+					return nil, err
+				}
+				continue
+			}
+
+			// merge other values by the mergo. copy the old value, mergo may change it.
+			tmp := map[string]any{k: deepCopy(dv)}
+			if err = mergo.Merge(&tmp, map[string]any{k: m.strip(sv)}, m.c.opts.MergeOptions...); err != nil {
+				return nil, fmt.Errorf("config: merge the key '%s' error: %w", keysToPath(keys, m.c.opts.Delimiter), err)
+			}
+			dst[k] = tmp[k]
+		}
+	}
+	return dst, nil
+}
+
+// collect the merge rules from the directive of the src map. eg: {"$merge": {"servers": "append"}}
+func (m *merger) collectDirective(src map[string]any, path []string) error {
+	if m.directive == "" {
+		return nil
+	}
+
+	mp, ok := src[m.directive].(map[string]any)
+	if !ok {
+		return nil
+	}
+
+	for _, relPath := range sortedKeys(mp) {
+		keys, err := m.c.parseKeys(relPath)
+		if err != nil {
+			return err
+		}
+
+		rule, err := ParseMergeRule(fmt.Sprint(mp[relPath]))
+		if err != nil {
+			return fmt.Errorf("%w, in the directive of the key '%s'", err, keysToPath(path, m.c.opts.Delimiter))
+		}
+
+		keys = append(path[:len(path):len(path)], keys...)
+		m.rules = append(m.rules, mergeRule{MergeRule: rule, keys: keys})
+	}
+	return nil
+}
+
+// get the merge rule of the key path. the directive of the value is first, then the latest matched rule.
+func (m *merger) ruleOf(keys []string, val any) (MergeRule, error) {
+	// the directive of the value. eg: {"db": {"$merge": "replace", ...}}
+	if m.directive != "" {
+		if mp, ok := val.(map[string]any); ok {
+			if s, ok := mp[m.directive].(string); ok {
+				rule, err := ParseMergeRule(s)
+				if err != nil {
+					err = fmt.Errorf("%w, in the directive of the key '%s'", err, keysToPath(keys, m.c.opts.Delimiter))
+				}
+				return rule, err
+			}
+		}
+	}
+
+	for i := len(m.rules) - 1; i >= 0; i-- {
+		if matchKeys(m.rules[i].keys, keys) {
+			return m.rules[i].MergeRule, nil
+		}
+	}
+	return MergeRule{Strategy: MergeDeep}, nil
+}
+
+// merge the list of maps by the identity field. the elements without the id will be appended.
+func (m *merger) mergeByID(dv, sv any, keys []string, idKey string) (any, error) {
+	dl, ok1 := toAnyList(dv)
+	sl, ok2 := toAnyList(sv)
+	if !ok1 || !ok2 {
+		return m.strip(sv), nil
+	}
+
+	index := make(map[string]int, len(dl))
+	for i, elem := range dl {
+		if mp, ok := elem.(map[string]any); ok && mp[idKey] != nil {
+			index[fmt.Sprint(mp[idKey])] = i
+		}
+	}
+
+	for _, elem := range sl {
+		sm, ok := elem.(map[string]any)
+		if !ok || sm[idKey] == nil {
+			dl = append(dl, m.strip(elem))
+			continue
+		}
+
+		id := fmt.Sprint(sm[idKey])
+		i, ok := index[id]
+		if !ok {
+			index[id] = len(dl)
+			dl = append(dl, m.strip(elem))
+			continue
+		}
+
+		if dm, ok := dl[i].(map[string]any); ok {
+			merged, err := m.mergeMap(dm, sm, append(keys[:len(keys):len(keys)], strconv.Itoa(i)))
+			if err != nil {
+				return nil, err
+			}
+			dl[i] = merged
+		} else {
+			dl[i] = m.strip(elem)
+		}
+	}
+	return dl, nil
+}
+
+// remove the directive keys from the value. returns a copy, the value may be the source data of the caller.
+func (m *merger) strip(val any) any {
+	if m.directive == "" {
+		return val
+	}
+
+	switch tv := val.(type) {
+	case map[string]any:
+		mp := make(map[string]any, len(tv))
+		for k, v := range tv {
+			if k != m.directive {
+				mp[k] = m.strip(v)
+			}
+		}
+		return mp
+	case []any:
+		list := make([]any, len(tv))
+		for i, v := range tv {
+			list[i] = m.strip(v)
+		}
+		return list
+	}
+	return val
+}
+
+// append the src list to the dst list. will replace the dst if any of them is not a list.
+func appendList(dst, src any) any {
+	dv, sv := reflect.ValueOf(dst), reflect.ValueOf(src)
+	if dv.Kind() != reflect.Slice || sv.Kind() != reflect.Slice {
+		return src
+	}
+
+	if dv.Type() == sv.Type() {
+		list := reflect.MakeSlice(dv.Type(), 0, dv.Len()+sv.Len())
+		return reflect.AppendSlice(reflect.AppendSlice(list, dv), sv).Interface()
+	}
+
+	dl, _ := toAnyList(dst)
+	sl, _ := toAnyList(src)
+	return append(dl, sl...)
+}
+
+// convert the slice value to a new []any
+func toAnyList(val any) ([]any, bool) {
+	rv := reflect.ValueOf(val)
+	if rv.Kind() != reflect.Slice {
+		return nil, false
+	}
+
+	list := make([]any, rv.Len())
+	for i := range list {
+		list[i] = rv.Index(i).Interface()
+	}
+	return list, true
+}
+
+// match the keys by the pattern keys, the pattern key "*" matches any key.
+func matchKeys(pattern, keys []string) bool {
+	if len(pattern) != len(keys) {
+		return false
+	}
+
+	for i, key := range pattern {
+		if key != "*" && key != keys[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,165 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/gookit/goutil/testutil/assert"
+)
+
+func TestParseMergeRule(t *testing.T) {
+	is := assert.New(t)
+
+	rule, err := ParseMergeRule("append")
+	is.NoErr(err)
+	is.Eq(MergeRule{Strategy: MergeAppend}, rule)
+
+	rule, err = ParseMergeRule("by-id")
+	is.NoErr(err)
+	is.Eq(MergeRule{Strategy: MergeByID, IDKey: "id"}, rule)
+
+	rule, err = ParseMergeRule(" by-id: name ")
+	is.NoErr(err)
+	is.Eq(MergeRule{Strategy: MergeByID, IDKey: "name"}, rule)
+
+	_, err = ParseMergeRule("invalid")
+	is.ErrMsg(err, `config: invalid merge strategy "invalid"`)
+	_, err = ParseMergeRule("replace:name")
+	is.ErrSubMsg(err, "does not support the id key")
+}
+
+func TestConfig_mergeRules(t *testing.T) {
+	is := assert.New(t)
+	c := New("test",
+		WithMergeRule("tags", MergeAppend),
+		WithMergeRule("db", MergeReplace),
+		WithMergeRule("plugins", MergeByID, "name"),
+		WithMergeRule("apps.*.ports", MergeAppend),
+		MergeNullDelete,
+	)
+
+	err := c.LoadStrings(JSON, `{
+		"name": "app",
+		"debug": true,
+		"tags": ["a", "b"],
+		"list": [1, 2],
+		"db": {"host": "localhost", "port": 3306},
+		"cache": {"host": "localhost", "port": 6379},
+		"plugins": [{"name": "auth", "enabled": true, "opts": {"ttl": 60}}, {"name": "log"}],
+		"apps": {"web": {"ports": [80]}}
+	}`)
+	is.NoErr(err)
+
+	err = c.LoadStrings(JSON, `{
+		"debug": null,
+		"tags": ["c"],
+		"list": [3],
+		"db": {"host": "db.local"},
+		"cache": {"host": "cache.local"},
+		"plugins": [{"name": "auth", "opts": {"retry": 3}}, {"name": "metrics"}, {"other": 1}],
+		"apps": {"web": {"ports": [443]}}
+	}`)
+	is.NoErr(err)
+
+	// null delete the key
+	is.False(c.Exists("debug"))
+	is.Eq("app", c.String("name"))
+	// append
+	is.Eq([]string{"a", "b", "c"}, c.Strings("tags"))
+	is.Eq([]int{80, 443}, c.Ints("apps.web.ports"))
+	// default: replace the list, deep merge the maps
+	is.Eq([]int{3}, c.Ints("list"))
+	is.Eq(map[string]any{"host": "cache.local", "port": float64(6379)}, c.Get("cache"))
+	// replace
+	is.Eq(map[string]any{"host": "db.local"}, c.Get("db"))
+	// by id
+	is.Eq([]any{
+		map[string]any{"name": "auth", "enabled": true, "opts": map[string]any{"ttl": float64(60), "retry": float64(3)}},
+		map[string]any{"name": "log"},
+		map[string]any{"name": "metrics"},
+		map[string]any{"other": float64(1)},
+	}, c.Get("plugins"))
+
+	// typed slice from Set
+	is.NoErr(c.Set("tags", []string{"x"}))
+	is.NoErr(c.LoadData(map[string]any{"tags": []string{"y"}}))
+	is.Eq([]string{"x", "y"}, c.Get("tags"))
+}
+
+func TestConfig_mergeNull(t *testing.T) {
+	is := assert.New(t)
+
+	// the null value don't override the old value by default
+	c := New("test")
+	is.NoErr(c.LoadStrings(JSON, `{"name": "app", "db": {"host": "localhost"}}`))
+	is.NoErr(c.LoadStrings(JSON, `{"name": null, "db": {"host": null, "port": null}}`))
+	is.Eq("app", c.String("name"))
+	is.Eq("localhost", c.String("db.host"))
+	// the new key is added
+	is.True(c.Exists("db.port"))
+	is.Nil(c.Get("db.port"))
+
+	// delete the key by the null value
+	c = New("test", MergeNullDelete)
+	is.NoErr(c.LoadStrings(JSON, `{"name": "app", "db": {"host": "localhost"}}`))
+	is.NoErr(c.LoadStrings(JSON, `{"name": null, "db": {"host": null}}`))
+	is.False(c.Exists("name"))
+	is.Eq(map[string]any{}, c.Get("db"))
+}
+
+func TestConfig_mergeDirective(t *testing.T) {
+	is := assert.New(t)
+	c := New("test", WithMergeDirective("$merge"))
+
+	err := c.LoadStrings(JSON, `{
+		"$merge": {"tags": "append"},
+		"tags": ["a"],
+		"db": {"host": "localhost", "port": 3306, "$merge": {"hosts": "append"}},
+		"plugins": [{"name": "auth", "enabled": true}]
+	}`)
+	is.NoErr(err)
+	// the directives are removed
+	is.False(c.Exists("$merge", false))
+	is.Eq(map[string]any{"host": "localhost", "port": float64(3306)}, c.Get("db"))
+
+	err = c.LoadStrings(JSON, `{
+		"$merge": {"tags": "append", "plugins": "by-id:name"},
+		"tags": ["b"],
+		"db": {"$merge": "replace", "host": "db.local"},
+		"plugins": [{"name": "auth", "enabled": false}]
+	}`)
+	is.NoErr(err)
+	is.Eq([]string{"a", "b"}, c.Strings("tags"))
+	is.Eq(map[string]any{"host": "db.local"}, c.Get("db"))
+	is.Eq([]any{map[string]any{"name": "auth", "enabled": false}}, c.Get("plugins"))
+
+	// the directive only works for the content it is in
+	err = c.LoadStrings(JSON, `{"tags": ["c"]}`)
+	is.NoErr(err)
+	is.Eq([]string{"c"}, c.Strings("tags"))
+
+	// invalid directive
+	err = c.LoadStrings(JSON, `{"$merge": {"tags": "invalid"}, "tags": ["d"]}`)
+	is.ErrSubMsg(err, `invalid merge strategy "invalid"`)
+	err = c.LoadStrings(JSON, `{"db": {"$merge": "invalid", "host": "x"}}`)
+	is.ErrSubMsg(err, "in the directive of the key 'db'")
+
+	// the source data of LoadData is not changed
+	src := map[string]any{
+		"$merge": map[string]any{"tags": "append"},
+		"tags":   []any{"e"},
+		"db":     map[string]any{"$merge": "replace", "host": "x"},
+		"new":    map[string]any{"$merge": "replace", "list": []any{map[string]any{"$merge": "replace"}}},
+	}
+	is.NoErr(c.LoadData(src))
+	is.Eq([]string{"c", "e"}, c.Strings("tags"))
+	is.Eq(map[string]any{"host": "x"}, c.Get("db"))
+	is.Eq(map[string]any{"list": []any{map[string]any{}}}, c.Get("new"))
+	is.Eq(map[string]any{"tags": "append"}, src["$merge"])
+	is.Eq(map[string]any{"$merge": "replace", "host": "x"}, src["db"])
+	is.Eq(map[string]any{"$merge": "replace"}, src["new"].(map[string]any)["list"].([]any)[0])
+
+	// the directive is disabled by default
+	c = New("test")
+	is.NoErr(c.LoadStrings(JSON, `{"$merge": {"tags": "append"}}`))
+	is.True(c.Exists("$merge", false))
+}
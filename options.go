@@ -5,16 +5,19 @@ import (
 
 	"dario.cat/mergo"
 	"github.com/go-viper/mapstructure/v2"
-	"github.com/gookit/goutil"
 )
 
 // there are some event names for config data changed.
 const (
-	OnSetValue   = "set.value"
-	OnSetData    = "set.data"
-	OnLoadData   = "load.data"
-	OnReloadData = "reload.data"
-	OnCleanData  = "clean.data"
+	OnSetValue    = "set.value"
+	OnSetData     = "set.data"
+	OnLoadData    = "load.data"
+	OnReloadData  = "reload.data"
+	OnCleanData   = "clean.data"
+	OnDeleteValue = "delete.value"
+	OnUpdateData  = "update.data"
+	// OnRollbackData on rollback the data to a history version. see Config.Rollback()
+	OnRollbackData = "rollback.data"
 )
 
 // HookFunc on config data changed.
@@ -40,6 +43,9 @@ type Options struct {
 	// Readonly config is readonly. default: false
 	Readonly bool
 	// EnableCache enable config data cache. default: false
+	//
+	// NOTE: the typed getters(eg: Int, String, StringMap) will take a mutex for the caches,
+	// so the concurrent readers are serialized.
 	EnableCache bool
 	// ParseKey support key path, allow finding value by key path. default: true
 	//
@@ -49,6 +55,10 @@ type Options struct {
 	//
 	// Deprecated: please set tag name by DecoderConfig, or use SetTagName()
 	TagName string
+	// ExpandKeys expand the top keys contain the Delimiter into nested maps on LoadData() and LoadSMap(). default: false
+	//
+	// eg: {"db.host": "localhost"} will be loaded as {"db": {"host": "localhost"}}
+	ExpandKeys bool
 	// Delimiter the delimiter char for split key path, on `ParseKey=true`.
 	//
 	// - default is '.'
@@ -59,10 +69,50 @@ type Options struct {
 	ReadFormat string
 	// DecoderConfig setting for binding data to struct. such as: TagName
 	DecoderConfig *mapstructure.DecoderConfig
-	// MergeOptions settings for merge two data
+	// MergeOptions settings for merge two data. use for merge the values of MergeDeep strategy, except maps.
 	MergeOptions []func(*mergo.Config)
+	// MergeRules the merge strategies by key path, the path supports the wildcard "*". eg: "servers", "apps.*.plugins"
+	//
+	// default is MergeDeep for all keys.
+	MergeRules map[string]MergeRule
+	// MergeDirective the reserved key for declare merge strategies in the config content. default is empty, disabled.
+	//
+	// eg: on set "$merge", the content: {"$merge": {"servers": "append", "plugins": "by-id:name"}, "db": {"$merge": "replace"}}
+	MergeDirective string
+	// MergeNullDelete an explicit null value in the new data will delete the key. default is false,
+	// the null value will not override the old value.
+	//
+	// NOTE: in YAML, a key without value is null too. eg: "key:"
+	MergeNullDelete bool
+	// DocumentsKey on load multi documents content(eg: YAML with `---`),
+	// if not empty, will save all documents as a list under the key.
+	//
+	// default is empty, will merge all documents in order.
+	DocumentsKey string
+	// KeyMatch mode for matching the keys. default is KeyMatchExact.
+	//
+	// If enabled, the keys will be normalized on load and lookup, see KeyMatch.Normalize().
+	// eg: on KeyMatchIgnoreStyle, "DB_HOST", "dbHost", "db-host" are all saved and matched as "db_host".
+	KeyMatch KeyMatch
+	// EncodeOptions default options for dump data. can be overridden on call DumpTo()
+	EncodeOptions *EncodeOptions
+	// DeprecateFunc on found deprecated key in the loaded data, see Config.Deprecate()
+	DeprecateFunc DeprecateFunc
+	// HistorySize the max number of the snapshots kept on reload data, use for Config.Rollback().
+	// default is 0, disabled.
+	HistorySize int
+	// Validator validate the updated data on Config.Update(), return error will roll back the changes.
+	Validator ValidateFunc
 	// HookFunc on data changed. you can do something...
+	//
+	// It is called after the lock is released, so it can read and write the config.
+	// Use Config.Subscribe() to add more listeners for the events.
+	//
+	// NOTE: if the events are delivering by other goroutine, the write returns after its events queued,
+	// and the hooks are called by the delivering goroutine later.
 	HookFunc HookFunc
+	// the listeners added by option funcs. eg: SaveFileOnSet()
+	listeners []*listener
 	// WatchChange bool
 }
 
@@ -109,6 +159,23 @@ func (o *Options) shouldAddHookFunc() bool {
 	return o.ParseTime || o.ParseEnv
 }
 
+// make encode options for dump data. return nil if there are no options.
+func (o *Options) makeEncodeOptions(fns []EncodeOptFn) *EncodeOptions {
+	if o.EncodeOptions == nil && len(fns) == 0 {
+		return nil
+	}
+
+	eo := &EncodeOptions{}
+	if o.EncodeOptions != nil {
+		*eo = *o.EncodeOptions
+	}
+
+	for _, fn := range fns {
+		fn(eo)
+	}
+	return eo
+}
+
 func (o *Options) makeDecoderConfig() *mapstructure.DecoderConfig {
 	var bindConf *mapstructure.DecoderConfig
 	if o.DecoderConfig == nil {
@@ -124,6 +191,11 @@ func (o *Options) makeDecoderConfig() *mapstructure.DecoderConfig {
 		}
 	}
 
+	// match the struct field names by the KeyMatch mode
+	if bindConf.MatchName == nil && o.KeyMatch != KeyMatchExact {
+		bindConf.MatchName = o.KeyMatch.matchName
+	}
+
 	// add hook on decode value to struct
 	if bindConf.DecodeHook == nil && o.shouldAddHookFunc() {
 		bindConf.DecodeHook = ValDecodeHookFunc(o.ParseEnv, o.ParseTime)
@@ -152,9 +224,26 @@ func ParseTime(opts *Options) { opts.ParseTime = true }
 // ParseDefault tag value on binding data to struct.
 func ParseDefault(opts *Options) { opts.ParseDefault = true }
 
+// ExpandKeys set expand the keys contain the delimiter on LoadData() and LoadSMap()
+func ExpandKeys(opts *Options) { opts.ExpandKeys = true }
+
 // Readonly set readonly
 func Readonly(opts *Options) { opts.Readonly = true }
 
+// WithKeyMatch set the key match mode. see Options.KeyMatch
+func WithKeyMatch(km KeyMatch) func(*Options) {
+	return func(opts *Options) {
+		opts.KeyMatch = km
+	}
+}
+
+// WithDeprecateFunc set the warning func for deprecated keys. see Options.DeprecateFunc
+func WithDeprecateFunc(fn DeprecateFunc) func(*Options) {
+	return func(opts *Options) {
+		opts.DeprecateFunc = fn
+	}
+}
+
 // Delimiter set delimiter char
 func Delimiter(sep byte) func(*Options) {
 	return func(opts *Options) {
@@ -162,17 +251,56 @@ func Delimiter(sep byte) func(*Options) {
 	}
 }
 
-// SaveFileOnSet set hook func, will panic on save error
+// SaveFileOnSet add a listener for save data to the file on the "set.*", OnDeleteValue and OnUpdateData events.
+// it will not replace the Options.HookFunc, the save error can be got by Config.Error()
+//
+// NOTE: same as the HookFunc, on concurrent writes the file may be saved after the Set() returned.
 func SaveFileOnSet(fileName string, format string) func(options *Options) {
 	return func(opts *Options) {
-		opts.HookFunc = func(event string, c *Config) {
-			if strings.HasPrefix(event, "set.") {
-				goutil.PanicErr(c.DumpToFile(fileName, format))
+		l := &listener{event: "*", fn: func(event string, c *Config) {
+			if !strings.HasPrefix(event, "set.") && event != OnDeleteValue && event != OnUpdateData {
+				return
+			}
+
+			if err := c.DumpToFile(fileName, format); err != nil {
+				c.addError(err)
 			}
+		}}
+		opts.listeners = append(opts.listeners[:len(opts.listeners):len(opts.listeners)], l)
+	}
+}
+
+// WithEncodeOptions set default encode options for dump data
+//
+// Usage:
+//
+//	c := config.New("app", config.WithEncodeOptions(config.EncodeIndent("  ")))
+func WithEncodeOptions(fns ...EncodeOptFn) func(*Options) {
+	return func(opts *Options) {
+		if opts.EncodeOptions == nil {
+			opts.EncodeOptions = &EncodeOptions{}
+		}
+
+		for _, fn := range fns {
+			fn(opts.EncodeOptions)
 		}
 	}
 }
 
+// WithHistorySize set the max number of the snapshots kept on reload data. see Options.HistorySize
+func WithHistorySize(size int) func(*Options) {
+	return func(opts *Options) {
+		opts.HistorySize = size
+	}
+}
+
+// WithValidator set the validator for Config.Update(). see Options.Validator
+func WithValidator(fn ValidateFunc) func(*Options) {
+	return func(opts *Options) {
+		opts.Validator = fn
+	}
+}
+
 // WithHookFunc set hook func
 func WithHookFunc(fn HookFunc) func(*Options) {
 	return func(opts *Options) {
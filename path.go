@@ -0,0 +1,218 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parse the key path to keys. the syntax:
+//
+//   - "a.b.c" split by the Options.Delimiter
+//   - `a\.b.c` the escaped delimiter, keys: ["a.b", "c"]
+//   - "list[0].name", "list[-1]" the slice index, negative index is from the end
+//   - `a["x.y"].b`, `a['x.y']` the quoted key, can contain the delimiter
+func parsePath(path string, sep byte) ([]string, error) {
+	// fast path: simple key path
+	if strings.IndexByte(path, '[') == -1 && strings.IndexByte(path, '\\') == -1 {
+		return strings.Split(path, string(sep)), nil
+	}
+
+	pks, err := parsePathKeys(path, sep)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, len(pks))
+	for i, pk := range pks {
+		keys[i] = pk.name
+	}
+	return keys, nil
+}
+
+// pathKey a key of the key path
+type pathKey struct {
+	name string
+	// is slice index, from the bracket. eg: "[0]", "[-1]"
+	index bool
+	// is quoted key, from the bracket. eg: `["x.y"]`
+	quoted bool
+	// is from the bracket. eg: "[0]", `["x.y"]`, "[?a==b]"
+	bracket bool
+}
+
+// parse the key path to keys, see parsePath()
+func parsePathKeys(path string, sep byte) ([]pathKey, error) {
+	var keys []pathKey
+	var sb strings.Builder
+	// the current key is written, for allow the empty key before '['. eg: "[0]"
+	written := false
+
+	for i := 0; i < len(path); i++ {
+		switch ch := path[i]; ch {
+		case '\\':
+			if i+1 >= len(path) {
+				return nil, pathError(path, "unexpected end after '\\\\'")
+			}
+			i++
+			sb.WriteByte(path[i])
+			written = true
+		case '[':
+			if written || sb.Len() > 0 {
+				keys = append(keys, pathKey{name: sb.String()})
+				sb.Reset()
+			}
+
+			end, key, err := parseBracket(path, i)
+			if err != nil {
+				return nil, err
+			}
+			keys = append(keys, key)
+			written = false
+
+			// after "]" must be the delimiter, '[' or end.
+			i = end
+			if i+1 < len(path) && path[i+1] != sep && path[i+1] != '[' {
+				return nil, pathError(path, fmt.Sprintf("unexpected char %q after ']'", path[i+1]))
+			}
+			if i+1 < len(path) && path[i+1] == sep {
+				i++
+				// "a[0]." is invalid
+				if i+1 == len(path) {
+					return nil, pathError(path, "unexpected end after the delimiter")
+				}
+			}
+		default:
+			if ch == sep {
+				keys = append(keys, pathKey{name: sb.String()})
+				sb.Reset()
+				written = false
+				continue
+			}
+			sb.WriteByte(ch)
+			written = true
+		}
+	}
+
+	if written || sb.Len() > 0 {
+		keys = append(keys, pathKey{name: sb.String()})
+	}
+	return keys, nil
+}
+
+// parse the bracket segment start at path[start] == '['. returns the index of ']' and the key.
+func parseBracket(path string, start int) (int, pathKey, error) {
+	i := start + 1
+	if i >= len(path) {
+		return 0, pathKey{}, pathError(path, "unclosed '['")
+	}
+
+	// quoted key. eg: ["x.y"] ['x.y']
+	if quote := path[i]; quote == '"' || quote == '\'' {
+		var sb strings.Builder
+		for i++; i < len(path); i++ {
+			ch := path[i]
+			if ch == '\\' && i+1 < len(path) {
+				i++
+				sb.WriteByte(path[i])
+				continue
+			}
+
+			if ch == quote {
+				if i+1 >= len(path) || path[i+1] != ']' {
+					return 0, pathKey{}, pathError(path, "expect ']' after the quoted key")
+				}
+				return i + 1, pathKey{name: sb.String(), quoted: true, bracket: true}, nil
+			}
+			sb.WriteByte(ch)
+		}
+		return 0, pathKey{}, pathError(path, "unclosed quoted key")
+	}
+
+	end := bracketEnd(path[i:])
+	if end == -1 {
+		return 0, pathKey{}, pathError(path, "unclosed '['")
+	}
+
+	key := strings.TrimSpace(path[i : i+end])
+	if key == "" {
+		return 0, pathKey{}, pathError(path, "empty index in '[]'")
+	}
+
+	_, err := strconv.Atoi(key)
+	return i + end, pathKey{name: key, index: err == nil, bracket: true}, nil
+}
+
+// find the index of the closing ']', skip the quoted strings. eg: `?name=="a]b"]`
+func bracketEnd(s string) int {
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		switch ch := s[i]; {
+		case quote != 0:
+			if ch == '\\' {
+				i++
+			} else if ch == quote {
+				quote = 0
+			}
+		case ch == '"' || ch == '\'':
+			quote = ch
+		case ch == ']':
+			return i
+		}
+	}
+	return -1
+}
+
+func pathError(path, reason string) error {
+	return fmt.Errorf("config: invalid key path %q: %s", path, reason)
+}
+
+// join the sub key to the parent path. will quote the key if it contains special chars.
+func joinPath(parent, key string, sep byte) string {
+	if strings.IndexByte(key, sep) > -1 || strings.ContainsAny(key, `[]\"`) {
+		key = `["` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(key) + `"]`
+		if parent == "" {
+			return key
+		}
+		return parent + key
+	}
+
+	if parent == "" {
+		return key
+	}
+	return parent + string(sep) + key
+}
+
+// build the key path from keys, it is the reverse of parsePath()
+func keysToPath(keys []string, sep byte) string {
+	var path string
+	for _, key := range keys {
+		path = joinPath(path, key, sep)
+	}
+	return path
+}
+
+// convert the keys to path keys, without the bracket info.
+func toPathKeys(keys []string) []pathKey {
+	pks := make([]pathKey, len(keys))
+	for i, key := range keys {
+		pks[i] = pathKey{name: key}
+	}
+	return pks
+}
+
+// parse the slice index, check it is valid. negative index is from the end. eg: -1 is the last one.
+func sliceIndex(k string, ln int) (int, bool) {
+	idx, err := strconv.Atoi(k)
+	if err != nil {
+		return 0, false
+	}
+
+	if idx < 0 {
+		idx += ln
+	}
+	if idx < 0 || idx >= ln {
+		return 0, false
+	}
+	return idx, true
+}
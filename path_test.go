@@ -0,0 +1,133 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/gookit/goutil/testutil/assert"
+)
+
+func TestParsePath(t *testing.T) {
+	is := assert.New(t)
+
+	tests := []struct {
+		path string
+		keys []string
+	}{
+		{"a.b.c", []string{"a", "b", "c"}},
+		{`a\.b.c`, []string{"a.b", "c"}},
+		{"list[0].name", []string{"list", "0", "name"}},
+		{"list[-1]", []string{"list", "-1"}},
+		{"list[0][1]", []string{"list", "0", "1"}},
+		{`a["x.y"].b`, []string{"a", "x.y", "b"}},
+		{`a['zh-CN.utf8']`, []string{"a", "zh-CN.utf8"}},
+		{`a["x\"y"]`, []string{"a", `x"y`}},
+		{`["a.b"].c`, []string{"a.b", "c"}},
+		{`a\[0]`, []string{"a[0]"}},
+	}
+	for _, tt := range tests {
+		keys, err := parsePath(tt.path, '.')
+		is.NoErr(err)
+		is.Eq(tt.keys, keys, "path: "+tt.path)
+	}
+
+	pks, err := parsePathKeys(`list[0].name["1"]`, '.')
+	is.NoErr(err)
+	is.Eq([]pathKey{{name: "list"}, {name: "0", index: true, bracket: true}, {name: "name"}, {name: "1", quoted: true, bracket: true}}, pks)
+
+	// custom delimiter
+	keys, err := parsePath(`a/b[1]/c\/d`, '/')
+	is.NoErr(err)
+	is.Eq([]string{"a", "b", "1", "c/d"}, keys)
+
+	// errors
+	for _, path := range []string{"a[0", `a["x`, `a["x"`, "a[]", "a[0]b", "a[0].", `a\`} {
+		_, err = parsePath(path, '.')
+		is.ErrSubMsg(err, "config: invalid key path", "path: "+path)
+	}
+}
+
+func TestJoinPath(t *testing.T) {
+	is := assert.New(t)
+
+	is.Eq("a.b", joinPath("a", "b", '.'))
+	is.Eq("b", joinPath("", "b", '.'))
+	is.Eq(`a["x.y"]`, joinPath("a", "x.y", '.'))
+	is.Eq(`["x.y"]`, joinPath("", "x.y", '.'))
+	is.Eq(`a["x\"y[0]"]`, joinPath("a", `x"y[0]`, '.'))
+	is.Eq(`a["x.y"].b`, keysToPath([]string{"a", "x.y", "b"}, '.'))
+
+	// round trip
+	keys := []string{"hosts", "db.local", `a\b`, "0"}
+	keys2, err := parsePath(keysToPath(keys, '.'), '.')
+	is.NoErr(err)
+	is.Eq(keys, keys2)
+}
+
+func TestConfig_keyPathSyntax(t *testing.T) {
+	is := assert.New(t)
+	c := New("test")
+	err := c.LoadStrings(JSON, `{
+		"hosts": {"db.local": {"port": 3306}},
+		"lang": {"zh-CN.utf8": "中文"},
+		"servers": [{"name": "a"}, {"name": "b"}, {"name": "c"}],
+		"top.key": "val"
+	}`)
+	is.NoErr(err)
+
+	is.Eq(3306, c.Int(`hosts["db.local"].port`))
+	is.Eq(3306, c.Int(`hosts.db\.local.port`))
+	is.Eq("中文", c.String(`lang['zh-CN.utf8']`))
+	is.Eq("a", c.String("servers[0].name"))
+	is.Eq("c", c.String("servers[-1].name"))
+	is.Eq("b", c.String("servers.-2.name"))
+	is.False(c.Exists("servers[-4]"))
+	is.True(c.Exists(`hosts["db.local"]`))
+	is.Eq("val", c.String("top.key"))
+	is.Eq("val", c.String(`["top.key"]`))
+
+	// invalid path
+	_, err = c.GetValueE("servers[0")
+	is.ErrSubMsg(err, "invalid key path")
+	is.False(c.Exists("servers[0"))
+
+	// set by path
+	is.NoErr(c.Set(`hosts["db.local"].user`, "root"))
+	is.Eq("root", c.String(`hosts["db.local"].user`))
+	is.NoErr(c.Set("servers[-1].name", "z"))
+	is.Eq("z", c.String("servers.2.name"))
+	is.NoErr(c.Set("servers[3]", map[string]any{"name": "d"}))
+	is.Eq("d", c.String("servers[-1].name"))
+	is.ErrSubMsg(c.Set("servers[5]", "x"), `invalid slice index "5"`)
+	is.ErrSubMsg(c.Set("servers[0].name.sub", "x"), "not a map or slice")
+	is.ErrSubMsg(c.Set("servers[0", "x"), "invalid key path")
+
+	// map struct
+	type server struct{ Name string }
+	s := server{}
+	is.NoErr(c.MapStruct("servers[-1]", &s))
+	is.Eq("d", s.Name)
+}
+
+func TestConfig_Set_typedContainers(t *testing.T) {
+	is := assert.New(t)
+	c := New("test")
+
+	is.NoErr(c.Set("tags[0]", "a"))
+	is.NoErr(c.Set("tags[1]", "b"))
+	is.Eq([]string{"a", "b"}, c.Get("tags"))
+
+	// convert to []any on the value type not matched
+	is.NoErr(c.Set("tags[1]", 2))
+	is.Eq([]any{"a", 2}, c.Get("tags"))
+
+	is.NoErr(c.Set("smap", map[string]string{"k": "v"}))
+	is.NoErr(c.Set("smap.k2", "v2"))
+	is.Eq(map[string]string{"k": "v", "k2": "v2"}, c.Get("smap"))
+	is.NoErr(c.Set("smap.k3.sub", "v3"))
+	is.Eq("v3", c.String("smap.k3.sub"))
+	is.Eq("v", c.String("smap.k"))
+
+	is.NoErr(c.Set("items[0].name", "a"))
+	is.Eq("a", c.String("items[0].name"))
+	is.ErrSubMsg(c.Set("list[1]", "a"), `invalid slice index "1"`)
+}
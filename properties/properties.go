@@ -2,11 +2,12 @@
 Package properties is a driver use Java properties format content as config source
 
 Usage please see readme.
-
 */
 package properties
 
 import (
+	"strings"
+
 	"github.com/gookit/config/v2"
 	"github.com/gookit/properties"
 )
@@ -14,6 +15,9 @@ import (
 // Name string
 const Name = "properties"
 
+// DefaultListSep the default list separator of the typed decoder. eg: `ports = 80, 443`
+const DefaultListSep = ","
+
 var (
 	// Decoder the properties content decoder
 	Decoder config.Decoder = properties.Decode
@@ -22,5 +26,83 @@ var (
 	Encoder config.Encoder = properties.Encode
 
 	// Driver for properties
-	Driver = config.NewDriver(Name, Decoder, Encoder)
+	Driver = &propDriver{StdDriver: config.NewDriver(Name, Decoder, Encoder)}
+
+	// TypedDecoder the properties content decoder with typed values. see DecodeTyped
+	TypedDecoder config.Decoder = DecodeTyped
+
+	// TypedDriver for properties, will infer value types on decode. see DecodeTyped
+	TypedDriver = &propDriver{
+		StdDriver: config.NewDriver(Name, TypedDecoder, Encoder),
+		typed:     true,
+	}
 )
+
+// propDriver support collect comments on decode.
+type propDriver struct {
+	*config.StdDriver
+	typed bool
+}
+
+// DecodeWithComments decode properties content and collect comments, the comments key is the property name.
+func (d *propDriver) DecodeWithComments(blob []byte, v any) (map[string]string, error) {
+	if d.typed {
+		return decodeTyped(blob, v, DefaultListSep)
+	}
+
+	p := properties.NewParser()
+	if err := p.ParseBytes(blob); err != nil {
+		return nil, err
+	}
+	return p.Comments(), p.Decode(v)
+}
+
+// DecodeTyped decode properties content with typed values.
+//
+//   - value will infer to bool, int, float and list. eg: `[a, b]`, `a, b`, see config.InferValue
+//   - key name will be split by '.' to nested maps. eg: `db.master.host`
+//   - the key ends with "[]" will collect values to a slice. eg: `arr[] = val`
+//
+// NOTE: the value contains DefaultListSep will be split to list. eg: `desc = Hello, world`,
+// use NewTypedDecoder("") to disable it.
+func DecodeTyped(blob []byte, ptr any) error {
+	return NewTypedDecoder(DefaultListSep)(blob, ptr)
+}
+
+// NewTypedDecoder create a typed properties decoder with custom list separator.
+// if listSep is not empty, will split the value contains it to list. eg: "," for `ports = 80, 443`
+func NewTypedDecoder(listSep string) config.Decoder {
+	return func(blob []byte, ptr any) error {
+		_, err := decodeTyped(blob, ptr, listSep)
+		return err
+	}
+}
+
+func decodeTyped(blob []byte, ptr any, listSep string) (map[string]string, error) {
+	var err error
+	data := make(map[string]any)
+
+	p := properties.NewParser(func(opts *properties.Options) {
+		opts.BeforeCollect = func(name string, val any) any {
+			str, ok := val.(string)
+			if err != nil || !ok {
+				return val
+			}
+
+			if key, isSlice := strings.CutSuffix(name, "[]"); isSlice {
+				err = config.CollectValue(data, strings.Split(key, "."), config.InferValue(str, ""), true)
+			} else {
+				err = config.CollectValue(data, strings.Split(name, "."), config.InferValue(str, listSep), false)
+			}
+			return val
+		}
+	})
+
+	if err1 := p.ParseBytes(blob); err1 != nil {
+		return nil, err1
+	}
+	if err != nil {
+		return nil, err
+	}
+	return p.Comments(), config.DecodeMapTo(data, ptr, properties.DefaultTagName)
+}
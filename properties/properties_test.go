@@ -30,3 +30,69 @@ n=value
 	is.Nil(err)
 	is.Eq("value", m.N)
 }
+
+func TestDecodeTyped(t *testing.T) {
+	is := assert.New(t)
+
+	c := config.NewEmpty("test")
+	c.AddDriver(properties.TypedDriver)
+
+	err := c.LoadStrings(properties.Name, `
+name = app
+debug = false
+age = 23
+rate = 1.5
+ports = 80, 443
+tags2 = [a, b]
+arr[] = 1
+arr[] = true
+db.master.host = localhost
+db.master.port = 3306
+`)
+	is.NoErr(err)
+	is.Eq("app", c.Get("name"))
+	is.Eq(false, c.Get("debug"))
+	is.Eq(23, c.Int("age"))
+	is.Eq(1.5, c.Get("rate"))
+	is.Eq([]int{80, 443}, c.Ints("ports"))
+	is.Eq([]any{80, 443}, c.Get("ports"))
+	is.Eq([]string{"a", "b"}, c.Strings("tags2"))
+	is.Eq([]any{1, true}, c.Get("arr"))
+	is.Eq(3306, c.Get("db.master.port"))
+
+	type master struct {
+		Host  string `properties:"host"`
+		Ports []int  `properties:"ports"`
+	}
+	m := &master{}
+	err = properties.TypedDecoder([]byte("host = local\nports = 1, 2"), m)
+	is.NoErr(err)
+	is.Eq("local", m.Host)
+	is.Eq([]int{1, 2}, m.Ports)
+
+	// disable split list by the separator
+	mp := map[string]any{}
+	err = properties.NewTypedDecoder("")([]byte("desc = Hello, world"), &mp)
+	is.NoErr(err)
+	is.Eq("Hello, world", mp["desc"])
+
+	// conflict: parent is a value, in any order
+	err = properties.TypedDecoder([]byte("db = val\ndb.host = localhost"), &mp)
+	is.ErrSubMsg(err, `"db" is not a map`)
+	err = properties.TypedDecoder([]byte("db.host = localhost\ndb = val"), &map[string]any{})
+	is.ErrSubMsg(err, `cannot set value for "db", it is a map`)
+}
+
+func TestDriver_comments(t *testing.T) {
+	is := assert.New(t)
+
+	c := config.NewEmpty("test")
+	c.AddDriver(properties.Driver)
+	err := c.LoadStrings(properties.Name, `
+# the app name
+name = app
+`)
+	is.NoErr(err)
+	is.Eq("app", c.String("name"))
+	is.StrContains(c.Comments()["name"], "the app name")
+}
@@ -0,0 +1,337 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// QueryResult a matched value of the Query()
+type QueryResult struct {
+	// Path the concrete key path of the value. eg: "servers.0.host"
+	Path string
+	// Value a copy of the matched value
+	Value any
+}
+
+// Query find all matched values by the query expression, see Config.Query()
+func Query(expr string) ([]QueryResult, error) { return dc.Query(expr) }
+
+// Query find all matched values by the query expression. it extends the key path syntax:
+//
+//   - "servers.*.host" or "servers[*].host" the wildcard, match all elements of map or slice
+//   - "servers[?enabled==true].name" the filter, match the elements by the sub value.
+//     operators: ==, !=, >, >=, <, <=. the "[?enabled]" will check the sub value is truthy.
+//     the "@" is the element itself. eg: "ports[?@>=8080]"
+//     the quoted value can contain ']' and the escaped quote. eg: `list[?name=="a]b"]`
+//
+// The key aliases on the leading keys before the first wildcard or filter are resolved, as Get() does.
+// eg: "database.*" -> "db.*", the result Path is the target key path.
+//
+// The results are in order: slice by index, map by sorted key.
+//
+// Usage:
+//
+//	rs, err := c.Query("servers[?port>=8080].host")
+//	for _, r := range rs {
+//		fmt.Println(r.Path, r.Value) // eg: servers.1.host b.local
+//	}
+func (c *Config) Query(expr string) ([]QueryResult, error) {
+	sep := c.opts.Delimiter
+	if expr = formatKey(expr, string(sep)); expr == "" {
+		return nil, ErrKeyIsEmpty
+	}
+
+	keys, err := parsePathKeys(expr, sep)
+	if err != nil {
+		return nil, err
+	}
+
+	km := c.opts.KeyMatch
+	segs := make([]querySeg, len(keys))
+	for i, pk := range keys {
+		segs[i].key = km.Normalize(pk.name)
+		if pk.quoted {
+			continue
+		}
+
+		if pk.name == "*" {
+			segs[i].wildcard = true
+		} else if pk.bracket && strings.HasPrefix(pk.name, "?") {
+			if segs[i].filter, err = parseFilter(pk.name[1:], sep); err != nil {
+				return nil, fmt.Errorf("config: invalid query %q: %w", expr, err)
+			}
+			c.normKeys(segs[i].filter.keys)
+		}
+	}
+
+	q := &querier{segs: c.resolveQueryAlias(segs), sep: sep}
+	q.find(c.rawData(), 0, "")
+	return q.results, nil
+}
+
+// resolve the aliases on the leading concrete keys of the query. eg: "database.*" -> "db.*"
+func (c *Config) resolveQueryAlias(segs []querySeg) []querySeg {
+	root, prefix := c, []string(nil)
+	if c.root != nil {
+		root, prefix = c.root, c.prefix
+	}
+	if len(root.aliases()) == 0 {
+		return segs
+	}
+
+	n := 0
+	for n < len(segs) && !segs[n].wildcard && segs[n].filter == nil {
+		n++
+	}
+
+	keys := make([]string, 0, len(prefix)+n)
+	keys = append(keys, prefix...)
+	for _, seg := range segs[:n] {
+		keys = append(keys, seg.key)
+	}
+
+	// the target of the scope view must be in the scope
+	keys, ok := root.resolveAliasKeys(keys)
+	if !ok || !hasKeysPrefix(keys, prefix) {
+		return segs
+	}
+
+	newSegs := make([]querySeg, 0, len(keys)-len(prefix)+len(segs)-n)
+	for _, key := range keys[len(prefix):] {
+		newSegs = append(newSegs, querySeg{key: key})
+	}
+	return append(newSegs, segs[n:]...)
+}
+
+type querySeg struct {
+	key      string
+	wildcard bool
+	filter   *queryFilter
+}
+
+type querier struct {
+	segs    []querySeg
+	sep     byte
+	results []QueryResult
+}
+
+func (q *querier) find(item any, idx int, path string) {
+	if idx == len(q.segs) {
+		q.results = append(q.results, QueryResult{Path: path, Value: deepCopy(item)})
+		return
+	}
+
+	seg := q.segs[idx]
+	switch {
+	case seg.wildcard:
+		eachChild(item, func(key string, val any) {
+			q.find(val, idx+1, joinPath(path, key, q.sep))
+		})
+	case seg.filter != nil:
+		eachChild(item, func(key string, val any) {
+			if seg.filter.match(val) {
+				q.find(val, idx+1, joinPath(path, key, q.sep))
+			}
+		})
+	default:
+		val, err := findByKeys(item, []string{seg.key}, 0, q.sep)
+		if err != nil {
+			return
+		}
+
+		// use the real index for the negative index. eg: "list[-1]"
+		key := seg.key
+		if i, err := strconv.Atoi(key); err == nil && i < 0 {
+			key = strconv.Itoa(i + reflect.ValueOf(item).Len())
+		}
+		q.find(val, idx+1, joinPath(path, key, q.sep))
+	}
+}
+
+// iterate the children of the map or slice value. map keys are in sorted order.
+func eachChild(item any, fn func(key string, val any)) {
+	rv := reflect.ValueOf(item)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			fn(strconv.Itoa(i), rv.Index(i).Interface())
+		}
+	case reflect.Map:
+		mp := make(map[string]any, rv.Len())
+		keys := make([]string, 0, rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			key := fmt.Sprint(iter.Key().Interface())
+			mp[key] = iter.Value().Interface()
+			keys = append(keys, key)
+		}
+
+		sort.Strings(keys)
+		for _, key := range keys {
+			fn(key, mp[key])
+		}
+	}
+}
+
+// queryFilter eg: "enabled==true", "port>=8080", "meta.env=='prod'", "enabled"
+type queryFilter struct {
+	// sub key path of the element
+	keys []string
+	sep  byte
+	// empty means check the value is truthy
+	op  string
+	val any
+}
+
+// unescape the quoted literal value in the filter
+var literalUnescaper = strings.NewReplacer(`\\`, `\`, `\"`, `"`, `\'`, `'`)
+
+func parseFilter(expr string, sep byte) (*queryFilter, error) {
+	left, op, right := expr, "", ""
+	if i := strings.IndexAny(expr, "=!<>"); i > -1 {
+		left, op = expr[:i], expr[i:i+1]
+		if i+1 < len(expr) && expr[i+1] == '=' {
+			op += "="
+		}
+
+		if op == "=" || op == "!" {
+			return nil, fmt.Errorf("invalid operator %q in filter %q", op, expr)
+		}
+		right = strings.TrimSpace(expr[i+len(op):])
+	}
+
+	left = strings.TrimSpace(left)
+	if left == "" {
+		return nil, fmt.Errorf("missing the key in filter %q", expr)
+	}
+
+	// "@" is the element itself. eg: "ints[?@>3]"
+	var keys []string
+	if left != "@" {
+		var err error
+		if keys, err = parsePath(left, sep); err != nil {
+			return nil, err
+		}
+	}
+
+	f := &queryFilter{keys: keys, sep: sep, op: op}
+	if op != "" {
+		if right == "" {
+			return nil, fmt.Errorf("missing the value in filter %q", expr)
+		}
+		f.val = parseLiteral(right)
+	}
+	return f, nil
+}
+
+// parse the literal value: 'str', "str", true, false, null, number, or bare string.
+// the quoted string can contain the escaped quote. eg: "a\"b"
+func parseLiteral(s string) any {
+	if ln := len(s); ln >= 2 && (s[0] == '"' || s[0] == '\'') && s[ln-1] == s[0] {
+		return literalUnescaper.Replace(s[1 : ln-1])
+	}
+
+	switch s {
+	case "true":
+		return true
+	case "false":
+		return false
+	case "null":
+		return nil
+	}
+
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return f
+	}
+	return s
+}
+
+func (f *queryFilter) match(item any) bool {
+	val := item
+	if len(f.keys) > 0 {
+		var err error
+		if val, err = findByKeys(item, f.keys, 0, f.sep); err != nil {
+			return false
+		}
+	}
+
+	if f.op == "" {
+		return isTruthy(val)
+	}
+
+	var cmp int
+	switch lit := f.val.(type) {
+	case nil:
+		cmp = 1
+		if val == nil {
+			cmp = 0
+		}
+	case bool:
+		b, ok := val.(bool)
+		if !ok {
+			var err error
+			if b, err = strconv.ParseBool(fmt.Sprint(val)); err != nil {
+				return false
+			}
+		}
+
+		cmp = 1
+		if b == lit {
+			cmp = 0
+		}
+	case float64:
+		fv, err := strconv.ParseFloat(fmt.Sprint(val), 64)
+		if err != nil {
+			return false
+		}
+		cmp = compareFloat(fv, lit)
+	default: // string
+		cmp = strings.Compare(fmt.Sprint(val), lit.(string))
+	}
+
+	switch f.op {
+	case "==":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	case "<":
+		return cmp < 0
+	default: // "<="
+		return cmp <= 0
+	}
+}
+
+func compareFloat(a, b float64) int {
+	if a < b {
+		return -1
+	}
+	if a > b {
+		return 1
+	}
+	return 0
+}
+
+func isTruthy(val any) bool {
+	switch tv := val.(type) {
+	case nil:
+		return false
+	case bool:
+		return tv
+	case string:
+		return tv != "" && tv != "0" && tv != "false"
+	}
+
+	rv := reflect.ValueOf(val)
+	switch rv.Kind() {
+	case reflect.Map, reflect.Slice, reflect.Array:
+		return rv.Len() > 0
+	}
+	return !rv.IsZero()
+}
@@ -0,0 +1,184 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/gookit/goutil/testutil/assert"
+)
+
+func TestConfig_Query(t *testing.T) {
+	is := assert.New(t)
+	c := New("test")
+	err := c.LoadStrings(JSON, `{
+		"servers": [
+			{"host": "a.local", "port": 80, "enabled": true, "meta": {"env": "prod"}},
+			{"host": "b.local", "port": 8080, "enabled": false, "meta": {"env": "dev"}},
+			{"host": "c.local", "port": "9090", "enabled": "true"}
+		],
+		"db": {
+			"main": {"host": "db1"},
+			"backup": {"host": "db2"}
+		}
+	}`)
+	is.NoErr(err)
+
+	queryPaths := func(expr string) (paths []string, vals []any) {
+		rs, err := c.Query(expr)
+		is.NoErr(err)
+		for _, r := range rs {
+			paths = append(paths, r.Path)
+			vals = append(vals, r.Value)
+		}
+		return
+	}
+
+	// wildcard on slice
+	paths, vals := queryPaths("servers.*.host")
+	is.Eq([]string{"servers.0.host", "servers.1.host", "servers.2.host"}, paths)
+	is.Eq([]any{"a.local", "b.local", "c.local"}, vals)
+
+	paths, _ = queryPaths("servers[*].host")
+	is.Len(paths, 3)
+
+	// wildcard on map, sorted by key
+	paths, vals = queryPaths("db.*.host")
+	is.Eq([]string{"db.backup.host", "db.main.host"}, paths)
+	is.Eq([]any{"db2", "db1"}, vals)
+
+	// filters
+	paths, vals = queryPaths("servers[?enabled==true].host")
+	is.Eq([]string{"servers.0.host", "servers.2.host"}, paths)
+	is.Eq([]any{"a.local", "c.local"}, vals)
+
+	_, vals = queryPaths("servers[?enabled].host")
+	is.Eq([]any{"a.local", "c.local"}, vals)
+	_, vals = queryPaths("servers[?port>=8080].host")
+	is.Eq([]any{"b.local", "c.local"}, vals)
+	_, vals = queryPaths("servers[?port < 8080].host")
+	is.Eq([]any{"a.local"}, vals)
+	_, vals = queryPaths("servers[?meta.env=='dev'].host")
+	is.Eq([]any{"b.local"}, vals)
+	_, vals = queryPaths("servers[?meta.env!=prod].host")
+	is.Eq([]any{"b.local"}, vals)
+	_, vals = queryPaths("servers[?host==\"a.local\"].port")
+	is.Eq([]any{float64(80)}, vals)
+
+	// the quoted value can contain ']' and the escaped quote
+	c2 := New("test")
+	is.NoErr(c2.LoadStrings(JSON, `{"list": [{"name": "a]b", "id": 1}, {"name": "a\"]", "id": 2}]}`))
+	rs, err := c2.Query(`list[?name=="a]b"].id`)
+	is.NoErr(err)
+	is.Len(rs, 1)
+	is.Eq(float64(1), rs[0].Value)
+	rs, err = c2.Query(`list[?name=='a"]'].id`)
+	is.NoErr(err)
+	is.Len(rs, 1)
+	is.Eq(float64(2), rs[0].Value)
+	rs, err = c2.Query(`list[?name=="a\"]"].id`)
+	is.NoErr(err)
+	is.Len(rs, 1)
+	is.Eq(float64(2), rs[0].Value)
+	_, err = c2.Query(`list[?name=="a]`)
+	is.ErrSubMsg(err, "unclosed '['")
+
+	// concrete key and negative index
+	paths, vals = queryPaths("servers[-1].host")
+	is.Eq([]string{"servers.2.host"}, paths)
+	is.Eq([]any{"c.local"}, vals)
+
+	// whole element
+	rs, err = c.Query("servers[?port==80]")
+	is.NoErr(err)
+	is.Len(rs, 1)
+	is.Eq("servers.0", rs[0].Path)
+	is.Eq("prod", c.String(rs[0].Path+".meta.env"))
+
+	// no matches
+	rs, err = c.Query("servers[?port>10000].host")
+	is.NoErr(err)
+	is.Empty(rs)
+	rs, err = c.Query("not-exist.*")
+	is.NoErr(err)
+	is.Empty(rs)
+
+	// invalid
+	_, err = c.Query("")
+	is.ErrIs(err, ErrKeyIsEmpty)
+	_, err = c.Query("servers[?port=80]")
+	is.ErrSubMsg(err, "invalid operator")
+	_, err = c.Query("servers[?==80]")
+	is.ErrSubMsg(err, "missing the key")
+	_, err = c.Query("servers[?port>=]")
+	is.ErrSubMsg(err, "missing the value")
+	_, err = c.Query("servers[*")
+	is.Err(err)
+}
+
+func TestConfig_Query_alias(t *testing.T) {
+	is := assert.New(t)
+	c := New("test")
+	c.AliasKey("database", "db")
+	c.AliasKey("db.hosts", "db.servers")
+	is.NoErr(c.LoadStrings(JSON, `{"db": {"servers": [{"host": "db1"}, {"host": "db2"}], "port": 3306}}`))
+
+	rs, err := c.Query("database.*")
+	is.NoErr(err)
+	is.Eq([]QueryResult{
+		{"db.port", float64(3306)},
+		{"db.servers", []any{map[string]any{"host": "db1"}, map[string]any{"host": "db2"}}},
+	}, rs)
+
+	// chained alias
+	rs, err = c.Query("database.hosts[*].host")
+	is.NoErr(err)
+	is.Eq([]QueryResult{{"db.servers.0.host", "db1"}, {"db.servers.1.host", "db2"}}, rs)
+
+	// the alias key after the wildcard is not resolved
+	rs, err = c.Query("*.hosts")
+	is.NoErr(err)
+	is.Empty(rs)
+
+	// scope view
+	sub := c.Scope("db")
+	rs, err = sub.Query("hosts.*.host")
+	is.NoErr(err)
+	is.Eq([]QueryResult{{"servers.0.host", "db1"}, {"servers.1.host", "db2"}}, rs)
+}
+
+func TestConfig_Query_containers(t *testing.T) {
+	is := assert.New(t)
+	c := New("test")
+	c.SetData(map[string]any{
+		"anyMap": map[any]any{"b": 2, "a": 1},
+		"strs":   []string{"x", "y"},
+		"ints":   []int{3, 4},
+		"list":   []any{map[string]any{"name": "n1"}, map[any]any{"name": "n2"}},
+		`a.b`:    map[string]any{"c": 1},
+	})
+
+	rs, err := c.Query("anyMap.*")
+	is.NoErr(err)
+	is.Eq([]QueryResult{{"anyMap.a", 1}, {"anyMap.b", 2}}, rs)
+
+	rs, err = c.Query("strs[*]")
+	is.NoErr(err)
+	is.Eq([]QueryResult{{"strs.0", "x"}, {"strs.1", "y"}}, rs)
+
+	rs, err = c.Query("ints[?@>3]")
+	is.NoErr(err)
+	is.Eq([]QueryResult{{"ints.1", 4}}, rs)
+
+	rs, err = c.Query("list[?name==n2].name")
+	is.NoErr(err)
+	is.Eq([]QueryResult{{"list.1.name", "n2"}}, rs)
+
+	// the quoted key is not a wildcard, keys with special chars are quoted in the path
+	rs, err = c.Query(`["a.b"].*`)
+	is.NoErr(err)
+	is.Eq([]QueryResult{{`["a.b"].c`, 1}}, rs)
+
+	// package level
+	rs, err = Query("not-exist.*")
+	is.NoErr(err)
+	is.Empty(rs)
+}
@@ -1,6 +1,10 @@
 package config
 
 import (
+	"errors"
+	"maps"
+	"reflect"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -16,74 +20,8 @@ func Exists(key string, findByPath ...bool) bool { return dc.Exists(key, findByP
 
 // Exists key exists check
 func (c *Config) Exists(key string, findByPath ...bool) (ok bool) {
-	sep := c.opts.Delimiter
-	if key = formatKey(key, string(sep)); key == "" {
-		return
-	}
-
-	if _, ok = c.data[key]; ok {
-		return
-	}
-
-	// disable find by path.
-	if len(findByPath) > 0 && !findByPath[0] {
-		return
-	}
-
-	// has sub key? eg. "lang.dir"
-	if strings.IndexByte(key, sep) == -1 {
-		return
-	}
-
-	keys := strings.Split(key, string(sep))
-	topK := keys[0]
-
-	// find top item data based on top key
-	var item any
-	if item, ok = c.data[topK]; !ok {
-		return
-	}
-	for _, k := range keys[1:] {
-		switch typeData := item.(type) {
-		case map[string]int: // is map(from Set)
-			if item, ok = typeData[k]; !ok {
-				return
-			}
-		case map[string]string: // is map(from Set)
-			if item, ok = typeData[k]; !ok {
-				return
-			}
-		case map[string]any: // is map(decode from toml/json/yaml.v3)
-			if item, ok = typeData[k]; !ok {
-				return
-			}
-		case map[any]any: // is map(decode from yaml.v2)
-			if item, ok = typeData[k]; !ok {
-				return
-			}
-		case []int: // is array(is from Set)
-			i, err := strconv.Atoi(k)
-			if err != nil || i >= len(typeData) {
-				return false
-			}
-			item = typeData[i]
-		case []string: // is array(is from Set)
-			i, err := strconv.Atoi(k)
-			if err != nil || i >= len(typeData) {
-				return false
-			}
-			item = typeData[i]
-		case []any: // is array(load from file)
-			i, err := strconv.Atoi(k)
-			if err != nil || i >= len(typeData) {
-				return false
-			}
-			item = typeData[i]
-		default: // error
-			return false
-		}
-	}
-	return true
+	_, err := c.lookup(key, len(findByPath) == 0 || findByPath[0])
+	return err == nil
 }
 
 /*************************************************************
@@ -93,23 +31,27 @@ func (c *Config) Exists(key string, findByPath ...bool) (ok bool) {
 // Data return all config data
 func Data() map[string]any { return dc.Data() }
 
-// Data get all config data.
+// Data get a copy of all config data, change it will not affect the config.
 //
 // Note: will don't apply any options, like ParseEnv
 func (c *Config) Data() map[string]any {
-	return c.data
+	data := c.rawData()
+	if data == nil {
+		return nil
+	}
+	return deepCopy(data).(map[string]any)
 }
 
 // Sub return a map config data by key
 func Sub(key string) map[string]any { return dc.Sub(key) }
 
-// Sub get a map config data by key
+// Sub get a copy of the map config data by key
 //
 // Note: will don't apply any options, like ParseEnv
 func (c *Config) Sub(key string) map[string]any {
 	if mp, ok := c.GetValue(key); ok {
 		if mmp, ok := mp.(map[string]any); ok {
-			return mmp
+			return deepCopy(mmp).(map[string]any)
 		}
 	}
 	return nil
@@ -120,8 +62,9 @@ func Keys() []string { return dc.Keys() }
 
 // Keys get all config data
 func (c *Config) Keys() []string {
-	keys := make([]string, 0, len(c.data))
-	for key := range c.data {
+	data := c.rawData()
+	keys := make([]string, 0, len(data))
+	for key := range data {
 		keys = append(keys, key)
 	}
 	return keys
@@ -130,10 +73,12 @@ func (c *Config) Keys() []string {
 // Get config value by key string, support get sub-value by key path(eg. 'map.key'),
 func Get(key string, findByPath ...bool) any { return dc.Get(key, findByPath...) }
 
-// Get config value by key, findByPath default is true.
+// Get config value by key, findByPath default is true. the map and slice value is a copy.
 func (c *Config) Get(key string, findByPath ...bool) any {
-	val, _ := c.GetValue(key, findByPath...)
-	return val
+	if val, ok := c.GetValue(key, findByPath...); ok {
+		return deepCopy(val)
+	}
+	return nil
 }
 
 // GetValue get value by given key string. findByPath default is true.
@@ -147,106 +92,109 @@ func GetValue(key string, findByPath ...bool) (any, bool) {
 //   - ok is true, find value from config
 //   - ok is false, not found or error
 func (c *Config) GetValue(key string, findByPath ...bool) (value any, ok bool) {
+	value, err := c.GetValueE(key, findByPath...)
+	if err != nil {
+		// not found is not an error
+		if !errors.Is(err, ErrNotFound) {
+			c.addError(err)
+		}
+		return nil, false
+	}
+	return value, true
+}
+
+// GetValueE get value by given key string, findByPath default is true.
+func GetValueE(key string, findByPath ...bool) (any, error) {
+	return dc.GetValueE(key, findByPath...)
+}
+
+// GetValueE get value by given key string, findByPath default is true.
+//
+// Return error:
+//   - ErrKeyIsEmpty the key is empty
+//   - *NotFoundError the key does not exist, can be checked by errors.Is(err, ErrNotFound)
+//   - *PathError the key path traverses into a non-container value
+func (c *Config) GetValueE(key string, findByPath ...bool) (any, error) {
+	return c.lookup(key, len(findByPath) == 0 || findByPath[0])
+}
+
+// lookup value by key path from the current data.
+func (c *Config) lookup(key string, findByPath bool) (any, error) {
 	sep := c.opts.Delimiter
 	if key = formatKey(key, string(sep)); key == "" {
-		c.addError(ErrKeyIsEmpty)
-		return
+		return nil, ErrKeyIsEmpty
 	}
-
-	// if not is readonly
-	if !c.opts.Readonly {
-		c.lock.RLock()
-		defer c.lock.RUnlock()
+	if c.root != nil {
+		return c.root.lookup(c.scopeKey(key, findByPath), true)
 	}
+	key = c.resolveAlias(key)
+	data := c.getData()
 
 	// is top key
-	if value, ok = c.data[key]; ok {
-		return
+	if value, ok := data[c.opts.KeyMatch.Normalize(key)]; ok {
+		return value, nil
 	}
 
-	// disable find by path.
-	if len(findByPath) > 0 && !findByPath[0] {
-		// c.addError(ErrNotFound)
-		return
+	// disable find by path
+	if !findByPath {
+		return nil, &NotFoundError{Key: key}
 	}
 
-	// has sub key? eg. "lang.dir"
-	if strings.IndexByte(key, sep) == -1 {
-		// c.addError(ErrNotFound)
-		return
+	keys, err := parsePath(key, sep)
+	if err != nil {
+		return nil, err
 	}
 
-	keys := strings.Split(key, string(sep))
-	topK := keys[0]
-
-	// find top item data based on top key
-	var item any
-	if item, ok = c.data[topK]; !ok {
-		// c.addError(ErrNotFound)
-		return
+	// not has sub key. eg. "lang.dir"
+	single := len(keys) == 1 && keys[0] == key
+	item, ok := data[c.normKeys(keys)[0]]
+	if !ok || single {
+		return nil, &NotFoundError{Key: key}
 	}
+	return findByKeys(item, keys, 1, sep)
+}
+
+// find child value from the item by keys[start:]. keys is the full key path, use for the error.
+func findByKeys(item any, keys []string, start int, sep byte) (any, error) {
+	var ok bool
 
-	// find child
 	// NOTICE: don't merge case, will result in an error.
 	// e.g. case []int, []string
-	// OR
-	// case []int:
-	// case []string:
-	for _, k := range keys[1:] {
+	for i := start; i < len(keys); i++ {
+		k := keys[i]
 		switch typeData := item.(type) {
 		case map[string]int: // is map(from Set)
-			if item, ok = typeData[k]; !ok {
-				return
-			}
+			item, ok = typeData[k]
 		case map[string]string: // is map(from Set)
-			if item, ok = typeData[k]; !ok {
-				return
-			}
-		case map[string]any: // is map(decode from toml/json)
-			if item, ok = typeData[k]; !ok {
-				return
-			}
-		case map[any]any: // is map(decode from yaml)
-			if item, ok = typeData[k]; !ok {
-				return
-			}
+			item, ok = typeData[k]
+		case map[string]any: // is map(decode from toml/json/yaml)
+			item, ok = typeData[k]
+		case map[any]any: // is map(decode from yaml.v2)
+			item, ok = typeData[k]
 		case []int: // is array(is from Set)
-			i, err := strconv.Atoi(k)
-
-			// check slice index
-			if err != nil || len(typeData) <= i {
-				ok = false
-				c.addError(err)
-				return
+			var idx int
+			if idx, ok = sliceIndex(k, len(typeData)); ok {
+				item = typeData[idx]
 			}
-
-			item = typeData[i]
 		case []string: // is array(is from Set)
-			i, err := strconv.Atoi(k)
-			if err != nil || len(typeData) <= i {
-				ok = false
-				c.addError(err)
-				return
+			var idx int
+			if idx, ok = sliceIndex(k, len(typeData)); ok {
+				item = typeData[idx]
 			}
-
-			item = typeData[i]
 		case []any: // is array(load from file)
-			i, err := strconv.Atoi(k)
-			if err != nil || len(typeData) <= i {
-				ok = false
-				c.addError(err)
-				return
+			var idx int
+			if idx, ok = sliceIndex(k, len(typeData)); ok {
+				item = typeData[idx]
 			}
-
-			item = typeData[i]
 		default: // error
-			ok = false
-			c.addErrorf("cannot get value of the key '%s'", key)
-			return
+			return nil, &PathError{Key: keysToPath(keys, sep), Path: keysToPath(keys[:i], sep), Value: item}
 		}
-	}
 
-	return item, true
+		if !ok {
+			return nil, &NotFoundError{Key: keysToPath(keys, sep)}
+		}
+	}
+	return item, nil
 }
 
 /*************************************************************
@@ -280,8 +228,12 @@ func (c *Config) MustString(key string) string {
 
 func (c *Config) getString(key string) (value string, ok bool) {
 	// find from cache
-	if c.opts.EnableCache && len(c.strCache) > 0 {
-		value, ok = c.strCache[key]
+	data := c.dataPtr()
+	if c.opts.EnableCache {
+		if c.lockCaches(data) {
+			value, ok = c.strCache[key]
+		}
+		c.cacheLock.Unlock()
 		if ok {
 			return
 		}
@@ -292,31 +244,41 @@ func (c *Config) getString(key string) (value string, ok bool) {
 		return
 	}
 
-	switch typVal := val.(type) {
-	// from json `int` always is float64
-	case string:
-		value = typVal
-		if c.opts.ParseEnv {
-			value = envutil.ParseEnvValue(value)
-		}
-	default:
-		var err error
-		value, err = strutil.AnyToString(val, false)
-		if err != nil {
-			return "", false
-		}
+	value, err := c.toString(key, val)
+	if err != nil {
+		return "", false
 	}
 
 	// add cache
-	if ok && c.opts.EnableCache {
-		if c.strCache == nil {
-			c.strCache = make(map[string]string)
+	if c.opts.EnableCache {
+		if c.lockCaches(data) {
+			if c.strCache == nil {
+				c.strCache = make(map[string]string)
+			}
+			c.strCache[key] = value
 		}
-		c.strCache[key] = value
+		c.cacheLock.Unlock()
 	}
 	return
 }
 
+// convert value to string, will parse ENV var on Options.ParseEnv is true.
+func (c *Config) toString(key string, val any) (string, error) {
+	// from json `int` always is float64
+	if str, ok := val.(string); ok {
+		if c.opts.ParseEnv {
+			str = envutil.ParseEnvValue(str)
+		}
+		return str, nil
+	}
+
+	str, err := strutil.AnyToString(val, false)
+	if err != nil {
+		return "", &ConvertError{Key: key, Value: val, Type: "string", Err: err}
+	}
+	return str, nil
+}
+
 // Int get an int by key
 func Int(key string, defVal ...int) int { return dc.Int(key, defVal...) }
 
@@ -382,28 +344,11 @@ func Duration(key string, defVal ...time.Duration) time.Duration { return dc.Dur
 // The stored value may be a Go duration string (e.g. "300s", "1h30m", "20m").
 // A bare integer is treated as nanoseconds for backwards compatibility.
 func (c *Config) Duration(key string, defVal ...time.Duration) time.Duration {
-	str, exist := c.getString(key)
-	if !exist {
-		if len(defVal) > 0 {
-			return defVal[0]
-		}
-		return 0
-	}
-
-	// Prefer a Go duration string, e.g. "300s", "1h30m".
-	if dur, err := time.ParseDuration(str); err == nil {
-		return dur
-	}
-
-	// Backwards compatible: a bare integer is nanoseconds.
-	if n, err := strconv.ParseInt(str, 10, 64); err == nil {
-		return time.Duration(n)
-	}
-
-	if len(defVal) > 0 {
+	dur, err := c.DurationE(key)
+	if err != nil && len(defVal) > 0 {
 		return defVal[0]
 	}
-	return 0
+	return dur
 }
 
 // Float get a float64 value, if not found return default value
@@ -462,6 +407,204 @@ func (c *Config) Bool(key string, defVal ...bool) (value bool) {
 	return
 }
 
+/*************************************************************
+ * read config (basic data type) with error
+ *************************************************************/
+
+// StringE get a string value by key, return error on not found or convert failed.
+func StringE(key string) (string, error) { return dc.StringE(key) }
+
+// StringE get a string value by key, return error on not found or convert failed.
+//
+// Return error:
+//   - *NotFoundError the key does not exist, can be checked by errors.Is(err, ErrNotFound)
+//   - *ConvertError the value cannot be converted to string. eg: a map value
+//   - *PathError the key path traverses into a non-container value
+func (c *Config) StringE(key string) (string, error) {
+	val, err := c.GetValueE(key)
+	if err != nil {
+		return "", err
+	}
+	return c.convString(key, val, "string")
+}
+
+// IntE get an int value by key, return error on not found or convert failed.
+func IntE(key string) (int, error) { return dc.IntE(key) }
+
+// IntE get an int value by key, return error on not found or convert failed. see StringE()
+func (c *Config) IntE(key string) (int, error) {
+	val, err := c.GetValueE(key)
+	if err != nil {
+		return 0, err
+	}
+	return c.convInt(key, val)
+}
+
+// Int64E get an int64 value by key, return error on not found or convert failed.
+func Int64E(key string) (int64, error) { return dc.Int64E(key) }
+
+// Int64E get an int64 value by key, return error on not found or convert failed. see StringE()
+func (c *Config) Int64E(key string) (int64, error) {
+	val, err := c.GetValueE(key)
+	if err != nil {
+		return 0, err
+	}
+	return c.convInt64(key, val)
+}
+
+// UintE get an uint value by key, return error on not found or convert failed.
+func UintE(key string) (uint, error) { return dc.UintE(key) }
+
+// UintE get an uint value by key, return error on not found or convert failed. see StringE()
+func (c *Config) UintE(key string) (uint, error) {
+	val, err := c.GetValueE(key)
+	if err != nil {
+		return 0, err
+	}
+	return c.convUint(key, val)
+}
+
+// FloatE get a float64 value by key, return error on not found or convert failed.
+func FloatE(key string) (float64, error) { return dc.FloatE(key) }
+
+// FloatE get a float64 value by key, return error on not found or convert failed. see StringE()
+func (c *Config) FloatE(key string) (float64, error) {
+	val, err := c.GetValueE(key)
+	if err != nil {
+		return 0, err
+	}
+	return c.convFloat(key, val)
+}
+
+// BoolE get a bool value by key, return error on not found or convert failed.
+func BoolE(key string) (bool, error) { return dc.BoolE(key) }
+
+// BoolE get a bool value by key, return error on not found or convert failed.
+// the allowed values are same as Bool(). see StringE() for the errors.
+func (c *Config) BoolE(key string) (bool, error) {
+	val, err := c.GetValueE(key)
+	if err != nil {
+		return false, err
+	}
+	return c.convBool(key, val)
+}
+
+// DurationE get a time.Duration value by key, return error on not found or convert failed.
+func DurationE(key string) (time.Duration, error) { return dc.DurationE(key) }
+
+// DurationE get a time.Duration value by key, return error on not found or convert failed.
+//
+// The stored value may be a Go duration string (e.g. "300s", "1h30m", "20m").
+// A bare integer is treated as nanoseconds for backwards compatibility.
+func (c *Config) DurationE(key string) (time.Duration, error) {
+	val, err := c.GetValueE(key)
+	if err != nil {
+		return 0, err
+	}
+	return c.convDuration(key, val)
+}
+
+// convert the value to string, typ is the target type name for ConvertError.
+func (c *Config) convString(key string, val any, typ string) (string, error) {
+	// map, slice value cannot be converted to scalar value
+	switch reflect.Indirect(reflect.ValueOf(val)).Kind() {
+	case reflect.Map, reflect.Slice, reflect.Array, reflect.Struct:
+		return "", &ConvertError{Key: key, Value: val, Type: typ}
+	}
+
+	str, err := c.toString(key, val)
+	if ce, ok := err.(*ConvertError); ok {
+		ce.Type = typ
+	}
+	return str, err
+}
+
+func (c *Config) convInt(key string, val any) (int, error) {
+	str, err := c.convString(key, val, "int")
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := strconv.ParseInt(str, 10, 0)
+	if err != nil {
+		return 0, &ConvertError{Key: key, Value: val, Type: "int", Err: err}
+	}
+	return int(n), nil
+}
+
+func (c *Config) convInt64(key string, val any) (int64, error) {
+	str, err := c.convString(key, val, "int64")
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := strconv.ParseInt(str, 10, 64)
+	if err != nil {
+		return 0, &ConvertError{Key: key, Value: val, Type: "int64", Err: err}
+	}
+	return n, nil
+}
+
+func (c *Config) convUint(key string, val any) (uint, error) {
+	str, err := c.convString(key, val, "uint")
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := strconv.ParseUint(str, 10, 0)
+	if err != nil {
+		return 0, &ConvertError{Key: key, Value: val, Type: "uint", Err: err}
+	}
+	return uint(n), nil
+}
+
+func (c *Config) convFloat(key string, val any) (float64, error) {
+	str, err := c.convString(key, val, "float64")
+	if err != nil {
+		return 0, err
+	}
+
+	f, err := strconv.ParseFloat(str, 64)
+	if err != nil {
+		return 0, &ConvertError{Key: key, Value: val, Type: "float64", Err: err}
+	}
+	return f, nil
+}
+
+func (c *Config) convBool(key string, val any) (bool, error) {
+	str, err := c.convString(key, val, "bool")
+	if err != nil {
+		return false, err
+	}
+
+	switch strings.ToLower(str) {
+	case "", "0", "false", "no":
+		return false, nil
+	case "1", "true", "yes":
+		return true, nil
+	}
+	return false, &ConvertError{Key: key, Value: val, Type: "bool"}
+}
+
+func (c *Config) convDuration(key string, val any) (time.Duration, error) {
+	str, err := c.convString(key, val, "time.Duration")
+	if err != nil {
+		return 0, err
+	}
+
+	// Prefer a Go duration string, e.g. "300s", "1h30m".
+	dur, err := time.ParseDuration(str)
+	if err == nil {
+		return dur, nil
+	}
+
+	// Backwards compatible: a bare integer is nanoseconds.
+	if n, err1 := strconv.ParseInt(str, 10, 64); err1 == nil {
+		return time.Duration(n), nil
+	}
+	return 0, &ConvertError{Key: key, Value: val, Type: "time.Duration", Err: err}
+}
+
 /*************************************************************
  * read config (complex data type)
  *************************************************************/
@@ -478,7 +621,7 @@ func (c *Config) Ints(key string) (arr []int) {
 
 	switch typeData := rawVal.(type) {
 	case []int:
-		arr = typeData
+		arr = slices.Clone(typeData)
 	case []any:
 		for _, v := range typeData {
 			iv, err := mathutil.ToInt(v)
@@ -509,7 +652,7 @@ func (c *Config) IntMap(key string) (mp map[string]int) {
 
 	switch typeData := rawVal.(type) {
 	case map[string]int: // from Set
-		mp = typeData
+		mp = maps.Clone(typeData)
 	case map[string]any: // decode from json,toml
 		mp = make(map[string]int)
 		for k, v := range typeData {
@@ -550,10 +693,14 @@ func Strings(key string) []string { return dc.Strings(key) }
 func (c *Config) Strings(key string) (arr []string) {
 	var ok bool
 	// find from cache
-	if c.opts.EnableCache && len(c.sArrCache) > 0 {
-		arr, ok = c.sArrCache[key]
+	data := c.dataPtr()
+	if c.opts.EnableCache {
+		if c.lockCaches(data) {
+			arr, ok = c.sArrCache[key]
+		}
+		c.cacheLock.Unlock()
 		if ok {
-			return
+			return slices.Clone(arr)
 		}
 	}
 
@@ -564,7 +711,7 @@ func (c *Config) Strings(key string) (arr []string) {
 
 	switch typeData := rawVal.(type) {
 	case []string:
-		arr = typeData
+		arr = slices.Clone(typeData)
 	case []any:
 		for _, v := range typeData {
 			// arr = append(arr, fmt.Sprintf("%v", v))
@@ -577,10 +724,13 @@ func (c *Config) Strings(key string) (arr []string) {
 
 	// add cache
 	if c.opts.EnableCache {
-		if c.sArrCache == nil {
-			c.sArrCache = make(map[string]strArr)
+		if c.lockCaches(data) {
+			if c.sArrCache == nil {
+				c.sArrCache = make(map[string]strArr)
+			}
+			c.sArrCache[key] = slices.Clone(arr)
 		}
-		c.sArrCache[key] = arr
+		c.cacheLock.Unlock()
 	}
 	return
 }
@@ -604,10 +754,14 @@ func (c *Config) StringMap(key string) (mp map[string]string) {
 	var ok bool
 
 	// find from cache
-	if c.opts.EnableCache && len(c.sMapCache) > 0 {
-		mp, ok = c.sMapCache[key]
+	data := c.dataPtr()
+	if c.opts.EnableCache {
+		if c.lockCaches(data) {
+			mp, ok = c.sMapCache[key]
+		}
+		c.cacheLock.Unlock()
 		if ok {
-			return
+			return maps.Clone(mp)
 		}
 	}
 
@@ -618,7 +772,7 @@ func (c *Config) StringMap(key string) (mp map[string]string) {
 
 	switch typeData := rawVal.(type) {
 	case map[string]string: // from Set
-		mp = typeData
+		mp = maps.Clone(typeData)
 	case map[string]any: // decode from json,toml,yaml.v3
 		mp = make(map[string]string, len(typeData))
 
@@ -658,10 +812,13 @@ func (c *Config) StringMap(key string) (mp map[string]string) {
 
 	// add cache
 	if c.opts.EnableCache {
-		if c.sMapCache == nil {
-			c.sMapCache = make(map[string]strMap)
+		if c.lockCaches(data) {
+			if c.sMapCache == nil {
+				c.sMapCache = make(map[string]strMap)
+			}
+			c.sMapCache[key] = maps.Clone(mp)
 		}
-		c.sMapCache[key] = mp
+		c.cacheLock.Unlock()
 	}
 	return
 }
@@ -675,7 +832,7 @@ func SubDataMap(key string) maputil.Map { return dc.SubDataMap(key) }
 func (c *Config) SubDataMap(key string) maputil.Map {
 	if mp, ok := c.GetValue(key); ok {
 		if mmp, ok := mp.(map[string]any); ok {
-			return mmp
+			return deepCopy(mmp).(map[string]any)
 		}
 	}
 
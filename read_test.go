@@ -1,6 +1,7 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"testing"
 	"time"
@@ -499,3 +500,88 @@ func TestConfig_Duration_getter(t *testing.T) {
 	is.Eq(7*time.Second, c.Duration("missing", 7*time.Second))
 	is.Eq(time.Duration(0), c.Duration("missing"))
 }
+
+func TestConfig_getterE(t *testing.T) {
+	is := assert.New(t)
+	c := New("test")
+	err := c.LoadStrings(JSON, `{
+		"name": "app",
+		"age": 23,
+		"neg": -2,
+		"ratio": 0.5,
+		"debug": "yes",
+		"ttl": "10s",
+		"db": {"host": "localhost"},
+		"list": [1, 2]
+	}`)
+	is.NoErr(err)
+
+	str, err := c.StringE("name")
+	is.NoErr(err)
+	is.Eq("app", str)
+
+	n, err := c.IntE("age")
+	is.NoErr(err)
+	is.Eq(23, n)
+
+	i64, err := c.Int64E("list.1")
+	is.NoErr(err)
+	is.Eq(int64(2), i64)
+
+	f, err := c.FloatE("ratio")
+	is.NoErr(err)
+	is.Eq(0.5, f)
+
+	b, err := c.BoolE("debug")
+	is.NoErr(err)
+	is.True(b)
+
+	dur, err := c.DurationE("ttl")
+	is.NoErr(err)
+	is.Eq(10*time.Second, dur)
+
+	// not found
+	_, err = c.IntE("notExist")
+	is.ErrIs(err, ErrNotFound)
+	var nfe *NotFoundError
+	is.True(errors.As(err, &nfe))
+	is.Eq("notExist", nfe.Key)
+
+	_, err = c.StringE("list.5")
+	is.ErrIs(err, ErrNotFound)
+
+	// convert error
+	_, err = c.IntE("name")
+	var ce *ConvertError
+	is.True(errors.As(err, &ce))
+	is.Eq("int", ce.Type)
+	is.Eq("app", ce.Value)
+	is.ErrSubMsg(err, `cannot convert value "app"(string) of the key 'name' to int`)
+
+	_, err = c.UintE("neg")
+	is.True(errors.As(err, &ce))
+	is.Eq("uint", ce.Type)
+
+	_, err = c.BoolE("name")
+	is.ErrMsg(err, `config: cannot convert value "app"(string) of the key 'name' to bool`)
+
+	_, err = c.StringE("db")
+	is.True(errors.As(err, &ce))
+	is.Eq("string", ce.Type)
+
+	_, err = c.DurationE("name")
+	is.ErrSubMsg(err, "to time.Duration")
+
+	// path error
+	_, err = c.FloatE("db.host.name")
+	var pe *PathError
+	is.True(errors.As(err, &pe))
+	is.Eq("db.host", pe.Path)
+	is.Eq("localhost", pe.Value)
+	is.ErrMsg(err, "config: cannot get value of the key 'db.host.name', the value of 'db.host' is string, not a map or slice")
+	is.False(errors.Is(err, ErrNotFound))
+
+	// empty key
+	_, err = c.GetValueE("")
+	is.ErrIs(err, ErrKeyIsEmpty)
+}
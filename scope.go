@@ -0,0 +1,148 @@
+package config
+
+import "fmt"
+
+// Scope create a scoped view of the sub config by key path. see Config.Scope()
+func Scope(key string, opts ...OptionFn) *Config { return dc.Scope(key, opts...) }
+
+// ScopeE create a scoped view of the sub config by key path. see Config.ScopeE()
+func ScopeE(key string, opts ...OptionFn) (*Config, error) { return dc.ScopeE(key, opts...) }
+
+// Scope create a scoped view of the sub config by key path. eg: c.Scope("db")
+//
+// The view shares the data and lock with the parent config, all keys are relative to the key path.
+// Writes will go through to the parent config, and the Options.HookFunc of the view is only
+// fired on the data of the key path changed.
+//
+// The options of the view are copied from the parent config, and can be changed by opts.
+// NOTE: Options.EnableCache is always disabled on the view. will panic on the key is invalid, see ScopeE().
+//
+// The view with the HookFunc is kept by the root config, for fire the hook. if the hook need to be
+// removed later, use Subscribe() on the view instead.
+//
+// Usage:
+//
+//	db := c.Scope("db", func(opts *config.Options) {
+//		opts.HookFunc = func(event string, c *config.Config) {
+//			fmt.Println("db config changed:", event)
+//		}
+//	})
+//	host := db.String("host", "localhost") // same as c.String("db.host", "localhost")
+//	err := db.Set("port", 3306)             // same as c.Set("db.port", 3306)
+func (c *Config) Scope(key string, opts ...OptionFn) *Config {
+	s, err := c.ScopeE(key, opts...)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// ScopeE create a scoped view of the sub config by key path, returns error on the key is invalid or empty.
+// see Config.Scope()
+func (c *Config) ScopeE(key string, opts ...OptionFn) (*Config, error) {
+	root := c
+	if c.root != nil {
+		root = c.root
+	}
+
+	keys, err := c.parseKeys(key)
+	if err != nil {
+		return nil, fmt.Errorf("config: invalid scope key '%s': %w", key, err)
+	}
+
+	prefix := append(append([]string{}, c.prefix...), keys...)
+	prefix, _ = root.resolveAliasKeys(prefix)
+
+	newOpts := *c.opts
+	newOpts.EnableCache = false
+	newOpts.HookFunc = nil
+	newOpts.listeners = nil
+	for _, fn := range opts {
+		fn(&newOpts)
+	}
+
+	s := &Config{
+		name:      c.name + "." + key,
+		opts:      &newOpts,
+		root:      root,
+		prefix:    prefix,
+		scopePath: keysToPath(prefix, root.opts.Delimiter),
+		// share the drivers with the parent config
+		drivers:     c.drivers,
+		encoders:    c.encoders,
+		decoders:    c.decoders,
+		aliasMap:    c.aliasMap,
+		driverNames: c.driverNames,
+	}
+
+	if s.hasListeners() {
+		root.addScope(s)
+	}
+	return s, nil
+}
+
+// add the scope view for fire hooks on the sub data changed, will ignore the added scope.
+func (c *Config) addScope(s *Config) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for _, added := range c.scopes {
+		if added == s {
+			return
+		}
+	}
+	c.scopes = append(c.scopes, s)
+}
+
+// remove the scope view if it has no listeners, so it can be released.
+func (c *Config) removeScope(s *Config) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	// check on the lock, the listener may be added by other goroutine
+	if s.hasListeners() {
+		return
+	}
+
+	for i, added := range c.scopes {
+		if added == s {
+			c.scopes = append(c.scopes[:i:i], c.scopes[i+1:]...)
+			return
+		}
+	}
+}
+
+// Prefix get the key path of the scope view in the root config. it is empty on the root config.
+func (c *Config) Prefix() string { return c.scopePath }
+
+// get the full key path in the root config for the scope view.
+func (c *Config) scopeKey(key string, byPath bool) string {
+	if byPath {
+		return c.scopePath + string(c.root.opts.Delimiter) + key
+	}
+	return joinPath(c.scopePath, key, c.root.opts.Delimiter)
+}
+
+// get the raw data, it is the sub map of the root data for the scope view.
+func (c *Config) rawData() map[string]any {
+	if c.root == nil {
+		return c.getData()
+	}
+
+	val, err := c.root.lookup(c.scopePath, true)
+	if err != nil {
+		return nil
+	}
+
+	mp, _ := val.(map[string]any)
+	return mp
+}
+
+// wrap the data to the sub map of the scope path. eg: prefix: "db", data: {"host": "localhost"}
+// will return {"db": {"host": "localhost"}}
+func (c *Config) wrapScopeData(data any) map[string]any {
+	for i := len(c.prefix) - 1; i > 0; i-- {
+		data = map[string]any{c.prefix[i]: data}
+	}
+	return map[string]any{c.prefix[0]: data}
+}
@@ -0,0 +1,146 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/gookit/goutil/testutil"
+	"github.com/gookit/goutil/testutil/assert"
+)
+
+func TestConfig_Scope(t *testing.T) {
+	is := assert.New(t)
+	c := New("test", ParseEnv)
+	err := c.LoadStrings(JSON, `{
+		"name": "app",
+		"db": {
+			"host": "${DB_HOST | localhost}",
+			"port": 3306,
+			"replicas": [{"host": "r1"}, {"host": "r2"}],
+			"opts": {"timeout": "3s"}
+		}
+	}`)
+	is.NoErr(err)
+
+	db := c.Scope("db")
+	is.Eq("test.db", db.Name())
+	is.Eq("db", db.Prefix())
+	is.Eq("", c.Prefix())
+	is.False(db.IsEmpty())
+	is.Len(db.Keys(), 4)
+
+	// typed getters, defaults and env parsing
+	is.Eq("localhost", db.String("host"))
+	testutil.MockEnvValue("DB_HOST", "db.local", func(_ string) {
+		is.Eq("db.local", db.String("host"))
+	})
+	is.Eq(3306, db.Int("port"))
+	is.Eq(10, db.Int("not-exist", 10))
+	is.Eq("r2", db.String("replicas.1.host"))
+	is.True(db.Exists("opts.timeout"))
+	is.False(db.Exists("name"))
+	is.Eq("3s", db.Value("opts").Get("timeout").String())
+
+	// nested scope
+	opts := db.Scope("opts")
+	is.Eq("db.opts", opts.Prefix())
+	is.Eq("3s", opts.String("timeout"))
+
+	// structure
+	type DB struct {
+		Host string
+		Port int
+	}
+	st := &DB{}
+	is.NoErr(db.Decode(st))
+	is.Eq(DB{Host: "localhost", Port: 3306}, *st)
+
+	// writes go through to the parent
+	is.NoErr(db.Set("port", 3307))
+	is.Eq(3307, c.Int("db.port"))
+	is.NoErr(opts.Set("retry", 3))
+	is.Eq(3, c.Int("db.opts.retry"))
+	is.NoErr(db.LoadData(map[string]any{"user": "root"}))
+	is.Eq("root", c.String("db.user"))
+	is.NoErr(db.LoadStrings(JSON, `{"password": "pwd"}`))
+	is.Eq("pwd", c.String("db.password"))
+
+	// the parent changes are visible
+	is.NoErr(c.Set("db.host", "127.0.0.1"))
+	is.Eq("127.0.0.1", db.String("host"))
+	c.SetData(map[string]any{"db": map[string]any{"host": "new"}})
+	is.Eq("new", db.String("host"))
+	is.Eq(map[string]any{"host": "new"}, db.Data())
+
+	// scope on the not exists key
+	cache := c.Scope("cache")
+	is.True(cache.IsEmpty())
+	is.NoErr(cache.Set("ttl", 60))
+	is.Eq(60, c.Int("cache.ttl"))
+
+	db.ClearData()
+	is.True(db.IsEmpty())
+	is.True(c.Exists("db"))
+
+	// readonly
+	ro := c.Scope("db", Readonly)
+	is.ErrIs(ro.Set("host", "x"), ErrReadonly)
+
+	is.Panics(func() {
+		c.Scope("")
+	})
+
+	// returns error
+	s, err := c.ScopeE("")
+	is.Nil(s)
+	is.ErrIs(err, ErrKeyIsEmpty)
+	_, err = c.ScopeE(`db["host`)
+	is.ErrSubMsg(err, `config: invalid scope key 'db["host'`)
+	s, err = c.ScopeE("db")
+	is.NoErr(err)
+	is.Eq("db", s.Prefix())
+}
+
+func TestConfig_Scope_hooks(t *testing.T) {
+	is := assert.New(t)
+
+	var rootEvents, dbEvents []string
+	c := New("test", func(opts *Options) {
+		opts.HookFunc = func(event string, c *Config) {
+			rootEvents = append(rootEvents, event)
+		}
+	})
+
+	db := c.Scope("db", func(opts *Options) {
+		opts.HookFunc = func(event string, s *Config) {
+			is.Eq("db", s.Prefix())
+			dbEvents = append(dbEvents, event)
+		}
+	})
+
+	is.NoErr(c.Set("name", "app"))
+	is.NoErr(c.Set("dbx", "x"))
+	is.Empty(dbEvents)
+
+	is.NoErr(c.Set("db.host", "localhost"))
+	is.NoErr(db.Set("port", 3306))
+	is.NoErr(c.Set("db", map[string]any{"host": "new"}))
+	is.Eq([]string{OnSetValue, OnSetValue, OnSetValue}, dbEvents)
+
+	// whole data changed
+	is.NoErr(c.LoadStrings(JSON, `{"app": "demo"}`))
+	is.Eq(OnLoadData, dbEvents[3])
+
+	db.ClearData()
+	is.Eq(OnCleanData, dbEvents[4])
+	is.Len(rootEvents, 7)
+
+	// create the view on the empty config
+	c = New("test")
+	s := c.Scope("db", func(opts *Options) {
+		opts.HookFunc = func(event string, s *Config) {
+			dbEvents = append(dbEvents, s.Prefix())
+		}
+	})
+	is.NoErr(s.Set("host", "localhost"))
+	is.Eq("db", dbEvents[len(dbEvents)-1])
+}
@@ -0,0 +1,161 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// Snapshot an immutable view of the config data. see Config.Snapshot()
+type Snapshot struct {
+	version uint64
+	time    time.Time
+	opts    Options
+	data    map[string]any
+	// the key aliases relative to the data
+	aliases map[string]*keyAlias
+	// the readonly config for Get()
+	reader *Config
+}
+
+// Version get the data version of the snapshot. the version is increased on each data changed.
+func (s *Snapshot) Version() uint64 { return s.version }
+
+// Time get the created time of the snapshot
+func (s *Snapshot) Time() time.Time { return s.time }
+
+// Get the value by key path from the snapshot. returns a copy of the value, will return nil on not found.
+//
+// The lookup is same as Config.Get(), the Options.KeyMatch and the key aliases are applied.
+func (s *Snapshot) Get(key string) any {
+	val, err := s.reader.lookup(key, true)
+	if err != nil {
+		return nil
+	}
+	return deepCopy(val)
+}
+
+// Data get a copy of the snapshot data
+func (s *Snapshot) Data() map[string]any { return deepCopy(s.data).(map[string]any) }
+
+// Config create a readonly config with the snapshot data, use for read the data by the typed getters.
+func (s *Snapshot) Config() *Config {
+	opts := s.opts
+	opts.Readonly = true
+
+	c := &Config{name: fmt.Sprintf("snapshot-v%d", s.version), opts: &opts}
+	c.storeData(s.data)
+	if len(s.aliases) > 0 {
+		c.keyAliases.Store(&s.aliases)
+	}
+	return c
+}
+
+// create a snapshot of the data, the data should not be changed after.
+func (c *Config) newSnapshot(data map[string]any, version uint64) *Snapshot {
+	opts := *c.opts
+	opts.HookFunc, opts.listeners, opts.Validator = nil, nil, nil
+	if data == nil {
+		data = make(map[string]any)
+	}
+
+	s := &Snapshot{version: version, time: time.Now(), opts: opts, data: data, aliases: c.scopeAliases()}
+	s.reader = s.Config()
+	return s
+}
+
+// Snapshot get an immutable view of the current data, the changes of the config will not affect it.
+// It does not copy the data, since the data is never changed after published. For the scope view, it is the sub data.
+//
+// Usage:
+//
+//	snap := c.Snapshot()
+//	fmt.Println(snap.Version(), snap.Get("db.host"))
+func (c *Config) Snapshot() *Snapshot {
+	root := c
+	if c.root != nil {
+		root = c.root
+	}
+
+	// the version is increased with the data changed under the write lock, read them together.
+	root.lock.RLock()
+	defer root.lock.RUnlock()
+	return c.newSnapshot(c.rawData(), root.version.Load())
+}
+
+// Version get the current data version, it is increased on each data changed.
+func (c *Config) Version() uint64 {
+	if c.root != nil {
+		return c.root.version.Load()
+	}
+	return c.version.Load()
+}
+
+// History get the snapshots kept on reload data, in the version order. see Options.HistorySize
+func (c *Config) History() []*Snapshot {
+	if c.root != nil {
+		return c.root.History()
+	}
+
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return append([]*Snapshot(nil), c.history...)
+}
+
+// add the replaced data to the history, must call it with the write lock. the data should not be changed after.
+func (c *Config) pushHistory(data map[string]any, version uint64) {
+	size := c.opts.HistorySize
+	if size <= 0 {
+		return
+	}
+
+	c.history = append(c.history, c.newSnapshot(data, version))
+	if n := len(c.history); n > size {
+		c.history = append([]*Snapshot(nil), c.history[n-size:]...)
+	}
+}
+
+// Rollback restore the data of the version from the history. see Config.Rollback()
+func Rollback(version uint64) error { return dc.Rollback(version) }
+
+// Rollback restore the data of the version from the history, will fire the OnRollbackData event.
+// The current data will be added to the history, so it can be rolled back again.
+//
+// NOTE: the history is kept on reload data, and enabled by Options.HistorySize
+//
+// Usage:
+//
+//	c := config.New("app", config.WithHistorySize(5))
+//	// ... load files, bad config is reloaded
+//	history := c.History()
+//	err := c.Rollback(history[len(history)-1].Version())
+func (c *Config) Rollback(version uint64) error {
+	if c.root != nil {
+		return c.root.Rollback(version)
+	}
+	if c.opts.Readonly {
+		return ErrReadonly
+	}
+
+	c.lock.Lock()
+	var snap *Snapshot
+	for _, s := range c.history {
+		if s.version == version {
+			snap = s
+		}
+	}
+
+	if snap == nil {
+		c.lock.Unlock()
+		return fmt.Errorf("config: the version %d is not found in the history", version)
+	}
+
+	old := c.getData()
+	c.pushHistory(old, c.version.Load())
+	c.storeData(snap.data)
+	c.ClearCaches()
+	c.queueEvent(OnRollbackData, nil, c.collectChanges(OnRollbackData, nil, old, true, snap.data, true))
+	c.lock.Unlock()
+
+	c.flushEvents()
+	return nil
+}
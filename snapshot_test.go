@@ -0,0 +1,180 @@
+package config
+
+import (
+	"os"
+	"testing"
+
+	"github.com/gookit/goutil/testutil/assert"
+)
+
+func TestConfig_Snapshot(t *testing.T) {
+	is := assert.New(t)
+	c := New("test")
+	is.Eq(uint64(0), c.Version())
+
+	is.NoErr(c.LoadStrings(JSON, `{"name": "app", "db": {"host": "localhost", "port": 3306}}`))
+	is.NoErr(c.Set("db.port", 3307))
+	is.Eq(uint64(2), c.Version())
+
+	snap := c.Snapshot()
+	is.Eq(uint64(2), snap.Version())
+	is.False(snap.Time().IsZero())
+	is.Eq("localhost", snap.Get("db.host"))
+	is.Nil(snap.Get("not-exist"))
+
+	// the snapshot is not affected by the changes
+	is.NoErr(c.Set("db.host", "db.local"))
+	is.NoErr(c.Delete("name"))
+	is.Eq("localhost", snap.Get("db.host"))
+	is.Eq("app", snap.Get("name"))
+
+	// the returned values are copies
+	snap.Data()["name"] = "changed"
+	snap.Get("db").(map[string]any)["host"] = "changed"
+	is.Eq("app", snap.Get("name"))
+	is.Eq("localhost", snap.Get("db.host"))
+
+	// read by the typed getters
+	sc := snap.Config()
+	is.Eq(3307, sc.Int("db.port"))
+	is.ErrIs(sc.Set("name", "new"), ErrReadonly)
+
+	// the scope view
+	snap = c.Scope("db").Snapshot()
+	is.Eq(c.Version(), snap.Version())
+	is.Eq(map[string]any{"host": "db.local", "port": 3307}, snap.Data())
+}
+
+func TestSnapshot_Get_keyMatchAlias(t *testing.T) {
+	is := assert.New(t)
+	c := New("test", WithKeyMatch(KeyMatchIgnoreCase))
+	c.AliasKey("database", "db")
+	c.AliasKey("db.hostname", "db.host")
+	is.NoErr(c.LoadStrings(JSON, `{"Name": "app", "DB": {"Host": "localhost"}, "a.b": "dot"}`))
+
+	snap := c.Snapshot()
+	is.Eq(c.Get("NAME"), snap.Get("NAME"))
+	is.Eq("app", snap.Get("NAME"))
+	is.Eq("localhost", snap.Get("Db.HOST"))
+	is.Eq("localhost", snap.Get("database.hostname"))
+	is.Eq("localhost", snap.Get("DATABASE.HostName"))
+	is.Eq(map[string]any{"host": "localhost"}, snap.Get("database"))
+	// the top-level literal key
+	is.Eq("dot", snap.Get("a.b"))
+	is.Eq("localhost", snap.Config().String("database.hostname"))
+
+	// the aliases in the scope view
+	snap = c.Scope("db").Snapshot()
+	is.Eq("localhost", snap.Get("HostName"))
+	is.Nil(snap.Get("database"))
+}
+
+func TestConfig_Rollback(t *testing.T) {
+	is := assert.New(t)
+	file := t.TempDir() + "/app.json"
+	is.NoErr(os.WriteFile(file, []byte(`{"db": {"host": "localhost", "port": 3306}}`), 0644))
+
+	var events []string
+	c := New("test", WithHistorySize(2), WithHookFunc(func(event string, c *Config) {
+		events = append(events, event+":"+c.String("db.host"))
+	}))
+	is.NoErr(c.LoadFiles(file))
+	is.Empty(c.History())
+	v1 := c.Version()
+
+	// push a bad config and reload
+	is.NoErr(os.WriteFile(file, []byte(`{"db": {"host": "bad-host"}}`), 0644))
+	is.NoErr(c.ReloadFiles())
+	is.Eq("bad-host", c.String("db.host"))
+	is.Len(c.History(), 1)
+	is.Eq(v1, c.History()[0].Version())
+
+	// rollback to the previous version
+	events = nil
+	is.NoErr(c.Rollback(v1))
+	is.Eq("localhost", c.String("db.host"))
+	is.Eq(3306, c.Int("db.port"))
+	is.Eq([]string{"rollback.data:localhost"}, events)
+
+	// the replaced data is kept, can rollback again
+	history := c.History()
+	is.Len(history, 2)
+	is.Eq("bad-host", history[1].Get("db.host"))
+	is.NoErr(c.Rollback(history[1].Version()))
+	is.Eq("bad-host", c.String("db.host"))
+
+	// the history is bounded
+	is.Len(c.History(), 2)
+	is.ErrMsg(c.Rollback(100), "config: the version 100 is not found in the history")
+
+	// change after rollback does not affect the history
+	is.NoErr(c.Set("db.host", "other"))
+	is.Eq("bad-host", c.History()[0].Get("db.host"))
+
+	// disabled by default
+	c = New("test")
+	is.NoErr(c.LoadFiles(file))
+	is.NoErr(c.ReloadFiles())
+	is.Empty(c.History())
+}
+
+func TestConfig_Rollback_scopeReload(t *testing.T) {
+	is := assert.New(t)
+	file := t.TempDir() + "/db.json"
+	is.NoErr(os.WriteFile(file, []byte(`{"host": "localhost"}`), 0644))
+
+	c := New("test", WithHistorySize(3))
+	is.NoErr(c.Set("name", "app"))
+	db := c.Scope("db")
+	is.NoErr(db.LoadFiles(file))
+	v := c.Version()
+
+	is.NoErr(os.WriteFile(file, []byte(`{"host": "bad-host"}`), 0644))
+	is.NoErr(db.ReloadFiles())
+	is.Eq("bad-host", c.String("db.host"))
+	is.Len(db.History(), 1)
+
+	is.NoErr(db.Rollback(v))
+	is.Eq("localhost", c.String("db.host"))
+	is.Eq("app", c.String("name"))
+}
+
+func TestSnapshot_notAffectedByReadValues(t *testing.T) {
+	is := assert.New(t)
+	c := New("test", EnableCache)
+	is.NoErr(c.LoadStrings(JSON, `{"db": {"host": "localhost", "tags": ["a", "b"]}, "labels": {"env": "prod"}}`))
+	is.NoErr(c.Set("ports", []int{80, 443}))
+	is.NoErr(c.Set("names", []string{"app"}))
+	is.NoErr(c.Set("weights", map[string]int{"a": 1}))
+	snap := c.Snapshot()
+
+	// change the returned values
+	c.Sub("db")["host"] = "changed"
+	c.SubDataMap("db")["host"] = "changed"
+	c.Get("db").(map[string]any)["tags"].([]any)[0] = "changed"
+	c.Strings("db.tags")[1] = "changed"
+	c.Strings("names")[0] = "changed"
+	c.StringMap("labels")["env"] = "changed"
+	c.Ints("ports")[0] = 8080
+	c.IntMap("weights")["a"] = 2
+	rs, err := c.Query("db.*")
+	is.NoErr(err)
+	rs[1].Value.([]any)[0] = "changed"
+
+	// the cached values are not changed
+	c.Strings("db.tags")[0] = "changed"
+	c.StringMap("labels")["env"] = "changed"
+
+	is.Eq("localhost", snap.Get("db.host"))
+	is.Eq([]any{"a", "b"}, snap.Get("db.tags"))
+	is.Eq("prod", snap.Get("labels.env"))
+	is.Eq([]int{80, 443}, snap.Get("ports"))
+	is.Eq([]string{"app"}, snap.Get("names"))
+	is.Eq(map[string]int{"a": 1}, snap.Get("weights"))
+
+	is.Eq("localhost", c.String("db.host"))
+	is.Eq([]string{"a", "b"}, c.Strings("db.tags"))
+	is.Eq(map[string]string{"env": "prod"}, c.StringMap("labels"))
+	is.Eq([]int{80, 443}, c.Ints("ports"))
+	is.Eq(map[string]int{"a": 1}, c.IntMap("weights"))
+}
@@ -26,4 +26,23 @@ var Encoder config.Encoder = func(ptr any) (out []byte, err error) {
 }
 
 // Driver for toml format
-var Driver = config.NewDriver(config.Toml, Decoder, Encoder)
+var Driver = &tomlDriver{StdDriver: config.NewDriver(config.Toml, Decoder, Encoder)}
+
+type tomlDriver struct {
+	*config.StdDriver
+}
+
+// EncodeWith encode data with config.EncodeOptions.
+//
+// Indent is supported, it is used for the nested tables. keys are always sorted.
+func (d *tomlDriver) EncodeWith(v any, opts *config.EncodeOptions) ([]byte, error) {
+	if opts == nil || opts.Indent == "" {
+		return d.Encode(v)
+	}
+
+	buf := new(bytes.Buffer)
+	enc := toml.NewEncoder(buf)
+	enc.Indent = opts.Indent
+	err := enc.Encode(v)
+	return buf.Bytes(), err
+}
@@ -111,3 +111,15 @@ func TestDriver(t *testing.T) {
 	is.Nil(err)
 	is.Contains(string(out), `k = "v"`)
 }
+
+func TestDriver_EncodeWith(t *testing.T) {
+	is := assert.New(t)
+
+	c := config.NewEmpty("test")
+	c.AddDriver(Driver)
+	is.NoErr(c.LoadData(map[string]any{"db": map[string]any{"host": "localhost"}}))
+
+	bs, err := Driver.EncodeWith(c.Data(), &config.EncodeOptions{Indent: "\t"})
+	is.NoErr(err)
+	is.StrContains(string(bs), "[db]\n\thost = \"localhost\"")
+}
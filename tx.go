@@ -0,0 +1,119 @@
+package config
+
+import "fmt"
+
+// ValidateFunc validate the updated data before commit the Config.Update(). return error will roll back the changes.
+//
+// The c is a temporary config with the updated data, don't call the methods of the updating config in it.
+type ValidateFunc func(c *Config) error
+
+// Tx the transaction of the Config.Update(), the changes are applied on a copy-on-write view of the data.
+type Tx struct {
+	// the temporary config with the copy of the data. is a scope view on update a scope view.
+	c *Config
+	// there are changes by Set or Delete
+	changed bool
+}
+
+// Get value by key from the transaction data. see Config.Get()
+func (tx *Tx) Get(key string, findByPath ...bool) any { return tx.c.Get(key, findByPath...) }
+
+// Exists check the key exists in the transaction data. see Config.Exists()
+func (tx *Tx) Exists(key string, findByPath ...bool) bool { return tx.c.Exists(key, findByPath...) }
+
+// Set a value by key in the transaction. see Config.Set()
+func (tx *Tx) Set(key string, val any, setByPath ...bool) error {
+	err := tx.c.Set(key, val, setByPath...)
+	if err == nil {
+		tx.changed = true
+	}
+	return err
+}
+
+// Delete the value by key path in the transaction. see Config.Delete()
+func (tx *Tx) Delete(key string) error {
+	err := tx.c.Delete(key)
+	if err == nil {
+		tx.changed = true
+	}
+	return err
+}
+
+// Config get the temporary config of the transaction, use for read the data by the typed getters.
+// eg: tx.Config().Int("port")
+//
+// NOTE: should write the data by Tx.Set() and Tx.Delete()
+func (tx *Tx) Config() *Config { return tx.c }
+
+// Update apply multi changes on the default config. see Config.Update()
+func Update(fn func(tx *Tx) error) error { return dc.Update(fn) }
+
+// Update apply multi changes atomically. the readers will see all or none of the changes.
+//
+// The changes are applied on a copy of the data, and will be committed if fn returns nil and
+// the Options.Validator accepts the updated data, otherwise all changes are rolled back.
+// After commit, fire one OnUpdateData event and one change event for each changed key path, see OnChange().
+//
+// NOTE: the write lock is held when calling fn, so only use the tx methods in the fn.
+//
+// Usage:
+//
+//	err := c.Update(func(tx *config.Tx) error {
+//		if err := tx.Set("db.host", "db.local"); err != nil {
+//			return err
+//		}
+//		return tx.Delete("db.password")
+//	})
+func (c *Config) Update(fn func(tx *Tx) error) (err error) {
+	if c.opts.Readonly {
+		return ErrReadonly
+	}
+
+	root := c
+	if c.root != nil {
+		root = c.root
+	}
+
+	root.lock.Lock()
+	defer func() {
+		root.lock.Unlock()
+		root.flushEvents()
+	}()
+
+	tmp := root.newTxConfig()
+	tx := &Tx{c: tmp}
+	if c.root != nil {
+		tx.c = tmp.Scope(c.scopePath)
+	}
+
+	if err = fn(tx); err != nil || !tx.changed {
+		return err
+	}
+
+	if root.opts.Validator != nil {
+		if err = root.opts.Validator(tmp); err != nil {
+			return fmt.Errorf("config: validate the updated data error: %w", err)
+		}
+	}
+
+	// commit the changes
+	old, data := root.getData(), tmp.getData()
+	root.storeData(data)
+	root.ClearCaches()
+	root.queueEvent(OnUpdateData, c.prefix, root.collectChanges(OnUpdateData, nil, old, true, data, true))
+	return nil
+}
+
+// create a temporary config with the current data for the Tx. the hooks are not copied.
+//
+// NOTE: the data is not copied, the writes of the temporary config copy the changed path only.
+func (c *Config) newTxConfig() *Config {
+	opts := *c.opts
+	opts.EnableCache = false
+	opts.HookFunc, opts.listeners, opts.Validator = nil, nil, nil
+
+	tmp := &Config{name: c.name, opts: &opts}
+	tmp.keyAliases.Store(c.keyAliases.Load())
+	tmp.storeData(c.getData())
+	return tmp
+}
@@ -0,0 +1,140 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/gookit/goutil/testutil/assert"
+)
+
+func TestConfig_Update(t *testing.T) {
+	is := assert.New(t)
+
+	var events []string
+	c := New("test", WithHookFunc(func(event string, c *Config) {
+		events = append(events, event)
+	}))
+	is.NoErr(c.LoadStrings(JSON, `{"db": {"host": "localhost", "port": 3306, "password": "secret"}}`))
+
+	var changes []string
+	c.OnChange("db", func(ev ChangeEvent) {
+		changes = append(changes, ev.Source+":"+ev.Path)
+	})
+
+	events = nil
+	err := c.Update(func(tx *Tx) error {
+		is.NoErr(tx.Set("db.host", "db.local"))
+		is.NoErr(tx.Set("db.port", 3306))
+		is.NoErr(tx.Set("db.user", "root"))
+		is.NoErr(tx.Delete("db.password"))
+
+		// read the changes in the tx, the config is not changed
+		is.Eq("db.local", tx.Get("db.host"))
+		is.Eq("root", tx.Config().String("db.user"))
+		is.False(tx.Exists("db.password"))
+		is.Eq("localhost", c.getData()["db"].(map[string]any)["host"])
+		return nil
+	})
+	is.NoErr(err)
+
+	is.Eq(map[string]any{"host": "db.local", "port": 3306, "user": "root"}, c.Get("db"))
+	is.Eq([]string{OnUpdateData}, events)
+	is.Eq([]string{"update.data:db.host", "update.data:db.password", "update.data:db.user"}, changes)
+
+	// no changes
+	events = nil
+	is.NoErr(c.Update(func(tx *Tx) error { return nil }))
+	is.Empty(events)
+}
+
+func TestConfig_Update_rollback(t *testing.T) {
+	is := assert.New(t)
+
+	var events []string
+	c := New("test",
+		WithHookFunc(func(event string, c *Config) {
+			events = append(events, event)
+		}),
+		WithValidator(func(c *Config) error {
+			if c.Int("db.port") <= 0 {
+				return errors.New("invalid db.port")
+			}
+			return nil
+		}),
+	)
+	is.NoErr(c.LoadStrings(JSON, `{"db": {"host": "localhost", "port": 3306}}`))
+	events = nil
+
+	// fn returns error
+	errFail := errors.New("fail")
+	err := c.Update(func(tx *Tx) error {
+		is.NoErr(tx.Set("db.host", "db.local"))
+		return errFail
+	})
+	is.ErrIs(err, errFail)
+	is.Eq("localhost", c.String("db.host"))
+
+	// rejected by the validator
+	err = c.Update(func(tx *Tx) error {
+		is.NoErr(tx.Set("db.host", "db.local"))
+		return tx.Set("db.port", 0)
+	})
+	is.ErrMsg(err, "config: validate the updated data error: invalid db.port")
+	is.Eq("localhost", c.String("db.host"))
+	is.Eq(3306, c.Int("db.port"))
+
+	// panic in fn
+	is.Panics(func() {
+		_ = c.Update(func(tx *Tx) error {
+			_ = tx.Set("db.host", "db.local")
+			panic("fn panic")
+		})
+	})
+	is.Eq("localhost", c.String("db.host"))
+	is.Empty(events)
+
+	// readonly
+	c = New("test", Readonly)
+	is.ErrIs(c.Update(func(tx *Tx) error { return nil }), ErrReadonly)
+}
+
+func TestConfig_Update_scope(t *testing.T) {
+	is := assert.New(t)
+	c := New("test")
+	is.NoErr(c.LoadStrings(JSON, `{"db": {"host": "localhost"}, "name": "app"}`))
+
+	var events []string
+	db := c.Scope("db", WithHookFunc(func(event string, s *Config) {
+		events = append(events, event)
+	}))
+
+	err := db.Update(func(tx *Tx) error {
+		is.Eq("localhost", tx.Get("host"))
+		is.NoErr(tx.Set("host", "db.local"))
+		return tx.Set("port", 3306)
+	})
+	is.NoErr(err)
+	is.Eq(map[string]any{"host": "db.local", "port": 3306}, c.Get("db"))
+	is.Eq("app", c.String("name"))
+	is.Eq([]string{OnUpdateData}, events)
+}
+
+func TestSaveFileOnSet_update(t *testing.T) {
+	is := assert.New(t)
+	file := t.TempDir() + "/config.json"
+	c := New("test", SaveFileOnSet(file, JSON))
+
+	err := c.Update(func(tx *Tx) error {
+		is.NoErr(tx.Set("name", "app"))
+		// the file is written after commit
+		_, err := os.Stat(file)
+		is.True(os.IsNotExist(err))
+		return nil
+	})
+	is.NoErr(err)
+
+	bs, err := os.ReadFile(file)
+	is.NoErr(err)
+	is.Contains(string(bs), `"name":"app"`)
+}
@@ -1,9 +1,12 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"reflect"
+	"strconv"
 	"strings"
+	"unicode"
 
 	"github.com/go-viper/mapstructure/v2"
 	"github.com/gookit/goutil/envutil"
@@ -39,11 +42,276 @@ func ValDecodeHookFunc(parseEnv, parseTime bool) mapstructure.DecodeHookFunc {
 	}
 }
 
+// InferValue parse a string value to typed value. it is used by the drivers
+// that only output string values(eg: INI, properties) in typed mode.
+//
+//   - "true", "false" -> bool
+//   - "23", "-23" -> int
+//   - "2.3", "1e3" -> float64
+//   - "[a, b]" or "a, b"(listSep is not empty) -> []any, each element will be inferred.
+//
+// Other values will be returned as string.
+//
+// NOTE: set listSep only if the values are always lists, or the free text value will be split. eg: "Hello, world"
+func InferValue(str, listSep string) any {
+	s := strings.TrimSpace(str)
+	ln := len(s)
+	if ln == 0 {
+		return str
+	}
+
+	// inline array. eg: [a, b]
+	if ln > 1 && s[0] == '[' && s[ln-1] == ']' {
+		return inferList(s[1:ln-1], ",")
+	}
+	if listSep != "" && strings.Contains(s, listSep) {
+		return inferList(s, listSep)
+	}
+
+	switch strings.ToLower(s) {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+
+	if isNumberLike(s) {
+		if i, err := strconv.Atoi(s); err == nil {
+			return i
+		}
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return f
+		}
+	}
+	return str
+}
+
+// check the string can be parsed as number: start with digit or '.' after the optional sign.
+// avoid parse "Inf", "-Infinity", "NaN" as float, and keep the leading zero value as string. eg: "007", "-007"
+func isNumberLike(s string) bool {
+	if s[0] == '-' || s[0] == '+' {
+		s = s[1:]
+	}
+
+	if s == "" {
+		return false
+	}
+
+	c := s[0]
+	if c == '0' {
+		return len(s) == 1 || s[1] == '.'
+	}
+	return c >= '1' && c <= '9' || c == '.'
+}
+
+// CollectValue set the value to the data by the path keys, it is used by the typed drivers(eg: INI, properties).
+// The sub maps will be created if not exists, and the value will be appended to the slice on isSlice is true.
+//
+// Will return error on the path conflicts in any order. eg: "db = val" with "db.host = localhost"
+func CollectValue(data map[string]any, keys []string, val any, isSlice bool) error {
+	last := len(keys) - 1
+	for i, key := range keys[:last] {
+		sub, ok := data[key]
+		if !ok {
+			mp := make(map[string]any)
+			data[key] = mp
+			data = mp
+			continue
+		}
+
+		if data, ok = sub.(map[string]any); !ok {
+			return fmt.Errorf("config: cannot set value for %q, %q is not a map", strings.Join(keys, "."), strings.Join(keys[:i+1], "."))
+		}
+	}
+
+	key := keys[last]
+	old, exists := data[key]
+	if _, ok := old.(map[string]any); ok {
+		return fmt.Errorf("config: cannot set value for %q, it is a map", strings.Join(keys, "."))
+	}
+
+	if isSlice {
+		list, ok := old.([]any)
+		if exists && !ok {
+			return fmt.Errorf("config: cannot append value for %q, it is not a slice", strings.Join(keys, "."))
+		}
+		data[key] = append(list, val)
+	} else {
+		data[key] = val
+	}
+	return nil
+}
+
+// DecodeMapTo decode the collected data to ptr, the ptr can be a *map[string]any or struct pointer.
+// it is used by the typed drivers, see CollectValue()
+func DecodeMapTo(data map[string]any, ptr any, tagName string) error {
+	if mp, ok := ptr.(*map[string]any); ok {
+		if *mp == nil {
+			*mp = data
+		} else {
+			for k, v := range data {
+				(*mp)[k] = v
+			}
+		}
+		return nil
+	}
+
+	dec, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		TagName:          tagName,
+		Result:           ptr,
+		WeaklyTypedInput: true,
+	})
+	if err != nil {
+		return err
+	}
+	return dec.Decode(data)
+}
+
+func inferList(s, sep string) []any {
+	if strings.TrimSpace(s) == "" {
+		return []any{}
+	}
+
+	items := strings.Split(s, sep)
+	list := make([]any, 0, len(items))
+	for _, item := range items {
+		list = append(list, InferValue(strings.TrimSpace(item), ""))
+	}
+	return list
+}
+
+// KeyStyle for convert map keys.
+type KeyStyle string
+
+// There are supported key styles
+const (
+	// KeyStyleKeep keep the key as is
+	KeyStyleKeep KeyStyle = ""
+	// KeyStyleSnake eg: db_host
+	KeyStyleSnake KeyStyle = "snake"
+	// KeyStyleKebab eg: db-host
+	KeyStyleKebab KeyStyle = "kebab"
+	// KeyStyleCamel eg: dbHost
+	KeyStyleCamel KeyStyle = "camel"
+)
+
+// Format the key to the style.
+//
+// Example:
+//
+//	KeyStyleSnake.Format("dbHost") // "db_host"
+//	KeyStyleCamel.Format("DB_HOST") // "dbHost"
+func (ks KeyStyle) Format(key string) string {
+	if ks == KeyStyleKeep {
+		return key
+	}
+
+	words := splitKeyWords(key)
+	if len(words) == 0 {
+		return key
+	}
+
+	switch ks {
+	case KeyStyleSnake:
+		return strings.Join(words, "_")
+	case KeyStyleKebab:
+		return strings.Join(words, "-")
+	case KeyStyleCamel:
+		for i := 1; i < len(words); i++ {
+			words[i] = strings.ToUpper(words[i][:1]) + words[i][1:]
+		}
+		return strings.Join(words, "")
+	}
+	return key
+}
+
+// split key to lower case words by separator '_', '-', ' ' and case change.
+//
+// eg: "dbHost", "db_host", "DB_HOST", "db-host" -> ["db", "host"]
+func splitKeyWords(key string) []string {
+	var words []string
+	runes := []rune(key)
+
+	start := 0
+	for i := 0; i <= len(runes); i++ {
+		if i == len(runes) || runes[i] == '_' || runes[i] == '-' || runes[i] == ' ' {
+			if i > start {
+				words = append(words, strings.ToLower(string(runes[start:i])))
+			}
+			start = i + 1
+			continue
+		}
+
+		// case change: "dbHost" -> "db", "Host". "HTTPServer" -> "HTTP", "Server"
+		if i > start && unicode.IsUpper(runes[i]) {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || unicode.IsUpper(prev) && nextLower {
+				words = append(words, strings.ToLower(string(runes[start:i])))
+				start = i
+			}
+		}
+	}
+	return words
+}
+
+// deep copy the map and slice value, other values are returned as is.
+func deepCopy(val any) any {
+	switch tv := val.(type) {
+	case map[string]any:
+		mp := make(map[string]any, len(tv))
+		for k, v := range tv {
+			mp[k] = deepCopy(v)
+		}
+		return mp
+	case []any:
+		list := make([]any, len(tv))
+		for i, v := range tv {
+			list[i] = deepCopy(v)
+		}
+		return list
+	}
+
+	rv := reflect.ValueOf(val)
+	switch rv.Kind() {
+	case reflect.Map:
+		if rv.IsNil() {
+			return val
+		}
+
+		elemType := rv.Type().Elem()
+		mp := reflect.MakeMapWithSize(rv.Type(), rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			mp.SetMapIndex(iter.Key(), toReflectValue(deepCopy(iter.Value().Interface()), elemType))
+		}
+		return mp.Interface()
+	case reflect.Slice:
+		if rv.IsNil() {
+			return val
+		}
+
+		elemType := rv.Type().Elem()
+		list := reflect.MakeSlice(rv.Type(), rv.Len(), rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			list.Index(i).Set(toReflectValue(deepCopy(rv.Index(i).Interface()), elemType))
+		}
+		return list.Interface()
+	}
+	return val
+}
+
 // resolve format, check is alias
 func (c *Config) resolveFormat(f string) string {
 	if name, ok := c.aliasMap[f]; ok {
 		return name
 	}
+
+	regLock.RLock()
+	defer regLock.RUnlock()
+	if name, ok := regAliases[f]; ok {
+		return name
+	}
 	return f
 }
 
@@ -64,6 +332,8 @@ func SetDecoder(format string, decoder Decoder) {
 func (c *Config) SetDecoder(format string, decoder Decoder) {
 	format = c.resolveFormat(format)
 	c.decoders[format] = decoder
+	// custom decoder will override the driver
+	delete(c.drivers, format)
 }
 
 // SetDecoders set decoders
@@ -88,6 +358,8 @@ func SetEncoder(format string, encoder Encoder) {
 func (c *Config) SetEncoder(format string, encoder Encoder) {
 	format = c.resolveFormat(format)
 	c.encoders[format] = encoder
+	// custom encoder will override the driver
+	delete(c.drivers, format)
 }
 
 // SetEncoders set encoders
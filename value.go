@@ -0,0 +1,208 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// Value is a read-only wrapper of a config value, for fluent reads on nested data.
+//
+// Usage:
+//
+//	srv := c.Value("servers.0")
+//	host := srv.Get("host").String()
+//	srv.Get("ports").ForEach(func(key string, val config.Value) bool {
+//		fmt.Println(val.Int())
+//		return true
+//	})
+type Value struct {
+	c *Config
+	// full key path of the value
+	key string
+	raw any
+	err error
+}
+
+// Value get a Value wrapper by key path. if not found, Value.Exists() will return false.
+func (c *Config) Value(key string) Value {
+	raw, err := c.GetValueE(key)
+	return Value{c: c, key: key, raw: raw, err: err}
+}
+
+// Key get the full key path of the value
+func (v Value) Key() string { return v.key }
+
+// Exists check the value is exists
+func (v Value) Exists() bool { return v.err == nil }
+
+// Err get the lookup error, it is nil if the value exists. see Config.GetValueE()
+func (v Value) Err() error { return v.err }
+
+// Raw get the raw value. will return nil if not exists
+func (v Value) Raw() any { return v.raw }
+
+// Kind get the reflect kind of the raw value. will return reflect.Invalid if not exists or is nil.
+func (v Value) Kind() reflect.Kind {
+	return reflect.ValueOf(v.raw).Kind()
+}
+
+// Get sub value by key path. eg: c.Value("servers").Get("0.host")
+func (v Value) Get(subKey string) Value {
+	if v.err != nil {
+		return Value{c: v.c, key: v.joinKey(subKey), err: v.err}
+	}
+
+	sep := v.c.opts.Delimiter
+	if subKey = formatKey(subKey, string(sep)); subKey == "" {
+		return Value{c: v.c, key: v.key, err: ErrKeyIsEmpty}
+	}
+
+	subKeys, err := parsePath(subKey, sep)
+	if err != nil {
+		return Value{c: v.c, key: v.joinKey(subKey), err: err}
+	}
+	subKeys = v.c.normKeys(subKeys)
+
+	keys, _ := parsePath(v.key, sep)
+	start := len(keys)
+	keys = append(keys, subKeys...)
+
+	raw, err := findByKeys(v.raw, keys, start, sep)
+	return Value{c: v.c, key: v.joinKey(subKey), raw: raw, err: err}
+}
+
+// String get the string value. will parse ENV var on Options.ParseEnv is true.
+func (v Value) String(defVal ...string) string {
+	if v.err == nil {
+		if str, err := v.c.convString(v.key, v.raw, "string"); err == nil {
+			return str
+		}
+	}
+	return first(defVal)
+}
+
+// Int get the int value. return default value on not exists or convert failed.
+func (v Value) Int(defVal ...int) int {
+	if v.err == nil {
+		if n, err := v.c.convInt(v.key, v.raw); err == nil {
+			return n
+		}
+	}
+	return first(defVal)
+}
+
+// Int64 get the int64 value. return default value on not exists or convert failed.
+func (v Value) Int64(defVal ...int64) int64 {
+	if v.err == nil {
+		if n, err := v.c.convInt64(v.key, v.raw); err == nil {
+			return n
+		}
+	}
+	return first(defVal)
+}
+
+// Float get the float64 value. return default value on not exists or convert failed.
+func (v Value) Float(defVal ...float64) float64 {
+	if v.err == nil {
+		if f, err := v.c.convFloat(v.key, v.raw); err == nil {
+			return f
+		}
+	}
+	return first(defVal)
+}
+
+// Bool get the bool value. return default value on not exists or convert failed.
+func (v Value) Bool(defVal ...bool) bool {
+	if v.err == nil {
+		if b, err := v.c.convBool(v.key, v.raw); err == nil {
+			return b
+		}
+	}
+	return first(defVal)
+}
+
+// Duration get the time.Duration value. return default value on not exists or convert failed.
+func (v Value) Duration(defVal ...time.Duration) time.Duration {
+	if v.err == nil {
+		if d, err := v.c.convDuration(v.key, v.raw); err == nil {
+			return d
+		}
+	}
+	return first(defVal)
+}
+
+// Array get the sub values of the slice value. will return nil if the value is not a slice.
+func (v Value) Array() []Value {
+	rv := reflect.ValueOf(v.raw)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil
+	}
+
+	list := make([]Value, rv.Len())
+	for i := range list {
+		list[i] = v.child(strconv.Itoa(i), rv.Index(i).Interface())
+	}
+	return list
+}
+
+// Map get the sub values of the map value. will return nil if the value is not a map.
+func (v Value) Map() map[string]Value {
+	rv := reflect.ValueOf(v.raw)
+	if rv.Kind() != reflect.Map {
+		return nil
+	}
+
+	mp := make(map[string]Value, rv.Len())
+	iter := rv.MapRange()
+	for iter.Next() {
+		key := fmt.Sprint(iter.Key().Interface())
+		mp[key] = v.child(key, iter.Value().Interface())
+	}
+	return mp
+}
+
+// ForEach iterate the sub values of the map or slice value, stop on fn return false.
+//
+// The map keys are iterated in sorted order, the key of slice element is the index string.
+func (v Value) ForEach(fn func(key string, val Value) bool) {
+	if list := v.Array(); list != nil {
+		for i, sv := range list {
+			if !fn(strconv.Itoa(i), sv) {
+				return
+			}
+		}
+		return
+	}
+
+	mp := v.Map()
+	keys := make([]string, 0, len(mp))
+	for k := range mp {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if !fn(k, mp[k]) {
+			return
+		}
+	}
+}
+
+func (v Value) child(subKey string, raw any) Value {
+	return Value{c: v.c, key: joinPath(v.key, subKey, v.c.opts.Delimiter), raw: raw}
+}
+
+func (v Value) joinKey(subKey string) string {
+	return v.key + string(v.c.opts.Delimiter) + subKey
+}
+
+// get the first element of the list, or zero value if empty.
+func first[T any](list []T) (val T) {
+	if len(list) > 0 {
+		val = list[0]
+	}
+	return
+}
@@ -0,0 +1,88 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gookit/goutil/testutil"
+	"github.com/gookit/goutil/testutil/assert"
+)
+
+func TestConfig_Value(t *testing.T) {
+	is := assert.New(t)
+	c := New("test", ParseEnv)
+	err := c.LoadStrings(JSON, `{
+		"name": "${APP_NAME | app}",
+		"servers": [
+			{"host": "a.local", "port": 80, "enabled": true, "timeout": "3s"},
+			{"host": "b.local", "port": "8080", "tags": ["web", "api"]}
+		],
+		"db": {"host": "localhost", "port": 3306}
+	}`)
+	is.NoErr(err)
+
+	v := c.Value("servers.0")
+	is.True(v.Exists())
+	is.NoErr(v.Err())
+	is.Eq(reflect.Map, v.Kind())
+	is.Eq("servers.0", v.Key())
+	is.Eq("a.local", v.Get("host").String())
+	is.Eq(80, v.Get("port").Int())
+	is.Eq(int64(80), v.Get("port").Int64())
+	is.Eq(float64(80), v.Get("port").Float())
+	is.True(v.Get("enabled").Bool())
+	is.Eq(3*time.Second, v.Get("timeout").Duration())
+	is.Eq("servers.0.host", v.Get("host").Key())
+
+	// sub path
+	sv := c.Value("servers")
+	is.Eq(reflect.Slice, sv.Kind())
+	is.Eq(8080, sv.Get("1.port").Int())
+	is.Eq("api", sv.Get("1.tags.1").String())
+	is.Len(sv.Array(), 2)
+	is.Eq("b.local", sv.Array()[1].Get("host").String())
+	is.Eq("servers.1", sv.Array()[1].Key())
+	is.Nil(sv.Map())
+
+	// map
+	mp := c.Value("db").Map()
+	is.Len(mp, 2)
+	is.Eq(3306, mp["port"].Int())
+	is.Nil(c.Value("db").Array())
+
+	var keys []string
+	c.Value("db").ForEach(func(key string, val Value) bool {
+		keys = append(keys, key+"="+val.String())
+		return true
+	})
+	is.Eq([]string{"host=localhost", "port=3306"}, keys)
+
+	var hosts []string
+	sv.ForEach(func(key string, val Value) bool {
+		hosts = append(hosts, key+":"+val.Get("host").String())
+		return false
+	})
+	is.Eq([]string{"0:a.local"}, hosts)
+
+	// not exists or convert failed
+	nv := c.Value("servers.5")
+	is.False(nv.Exists())
+	is.ErrIs(nv.Err(), ErrNotFound)
+	is.Eq(reflect.Invalid, nv.Kind())
+	is.Nil(nv.Raw())
+	is.False(nv.Get("host").Exists())
+	is.Eq("def", nv.Get("host").String("def"))
+	is.Eq(23, v.Get("host").Int(23))
+	is.Eq(0, v.Get("host").Int())
+	is.Eq("", v.String())
+	is.False(v.Get("host.sub").Exists())
+	is.Err(v.Get("host.sub").Err())
+	is.ErrIs(v.Get("").Err(), ErrKeyIsEmpty)
+
+	// ParseEnv
+	testutil.MockEnvValue("APP_NAME", "my-app", func(_ string) {
+		is.Eq("my-app", c.Value("name").String())
+	})
+	is.Eq("${APP_NAME | app}", c.Value("name").Raw())
+}
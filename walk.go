@@ -0,0 +1,93 @@
+package config
+
+import (
+	"errors"
+	"reflect"
+)
+
+// there are special errors for the WalkFunc.
+var (
+	// ErrSkipSubtree return it from the WalkFunc on a map or slice value, will skip walk the children of it.
+	ErrSkipSubtree = errors.New("skip this subtree")
+	// ErrStopWalk return it from the WalkFunc, will stop walk and the Walk() returns nil.
+	ErrStopWalk = errors.New("stop walk")
+)
+
+// WalkFunc the func for Config.Walk(). path is the full key path of the value, depth is start from 1.
+type WalkFunc func(path string, value any, depth int) error
+
+// Walk all config data. see Config.Walk()
+func Walk(fn WalkFunc) error { return dc.Walk(fn) }
+
+// Walk all config data in depth-first order, the map keys are in sorted order and the slice by index.
+//
+// The fn is called for every value, includes the map and slice values, before walk their children.
+// Return ErrSkipSubtree to skip the children, return ErrStopWalk to stop walk, other errors will stop and return it.
+//
+// NOTE: walk on a copy of the data, so the fn can call any methods of the config,
+// and changes to the value will not affect the config data.
+//
+// Usage:
+//
+//	err := c.Walk(func(path string, value any, depth int) error {
+//		if path == "secrets" {
+//			return config.ErrSkipSubtree
+//		}
+//		fmt.Println(path, value)
+//		return nil
+//	})
+func (c *Config) Walk(fn WalkFunc) error {
+	data := deepCopy(c.rawData())
+
+	err := walkValue(data, "", 0, c.opts.Delimiter, fn)
+	if errors.Is(err, ErrStopWalk) || errors.Is(err, ErrSkipSubtree) {
+		return nil
+	}
+	return err
+}
+
+func walkValue(item any, path string, depth int, sep byte, fn WalkFunc) (err error) {
+	if depth > 0 {
+		if err = fn(path, item, depth); err != nil {
+			if errors.Is(err, ErrSkipSubtree) {
+				return nil
+			}
+			return err
+		}
+	}
+
+	eachChild(item, func(key string, val any) {
+		if err == nil {
+			err = walkValue(val, joinPath(path, key, sep), depth+1, sep, fn)
+		}
+	})
+	return err
+}
+
+// AllKeys get all key paths of the leaf values. see Config.AllKeys()
+func AllKeys() []string { return dc.AllKeys() }
+
+// AllKeys get the full key paths of all leaf values, in the Walk() order.
+// the empty map or slice is a leaf value.
+//
+// eg: ["db.host", "db.port", "servers.0.host", "tags.0"]
+func (c *Config) AllKeys() []string {
+	var keys []string
+	_ = c.Walk(func(path string, value any, _ int) error {
+		if !hasChildren(value) {
+			keys = append(keys, path)
+		}
+		return nil
+	})
+	return keys
+}
+
+// check the value is a non-empty map or slice
+func hasChildren(val any) bool {
+	rv := reflect.ValueOf(val)
+	switch rv.Kind() {
+	case reflect.Map, reflect.Slice, reflect.Array:
+		return rv.Len() > 0
+	}
+	return false
+}
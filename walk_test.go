@@ -0,0 +1,81 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/gookit/goutil/testutil/assert"
+)
+
+func TestConfig_Walk(t *testing.T) {
+	is := assert.New(t)
+	c := New("test")
+	c.SetData(map[string]any{
+		"name":   "app",
+		"db":     map[string]any{"port": 3306, "host": "localhost"},
+		"yaml":   map[any]any{"b": 2, "a": []any{1}},
+		"tags":   []string{"x", "y"},
+		"empty":  map[string]any{},
+		"a.b":    1,
+		"secret": map[string]any{"key": "xxx"},
+	})
+
+	var lines []string
+	err := c.Walk(func(path string, value any, depth int) error {
+		if path == "secret" {
+			return ErrSkipSubtree
+		}
+		lines = append(lines, fmt.Sprintf("%d %s=%v", depth, path, value))
+		return nil
+	})
+	is.NoErr(err)
+	is.Eq([]string{
+		`1 ["a.b"]=1`,
+		"1 db=map[host:localhost port:3306]",
+		"2 db.host=localhost",
+		"2 db.port=3306",
+		"1 empty=map[]",
+		"1 name=app",
+		"1 tags=[x y]",
+		"2 tags.0=x",
+		"2 tags.1=y",
+		"1 yaml=map[a:[1] b:2]",
+		"2 yaml.a=[1]",
+		"3 yaml.a.0=1",
+		"2 yaml.b=2",
+	}, lines)
+
+	// stop
+	var paths []string
+	err = c.Walk(func(path string, value any, depth int) error {
+		paths = append(paths, path)
+		if path == "db.host" {
+			return ErrStopWalk
+		}
+		return nil
+	})
+	is.NoErr(err)
+	is.Eq([]string{`["a.b"]`, "db", "db.host"}, paths)
+
+	// custom error
+	err = c.Walk(func(path string, value any, depth int) error {
+		return errors.New("walk error")
+	})
+	is.ErrMsg(err, "walk error")
+
+	// call the config methods in fn
+	err = c.Walk(func(path string, value any, depth int) error {
+		if path == "db.host" {
+			return c.Set(path, "***")
+		}
+		return nil
+	})
+	is.NoErr(err)
+	is.Eq("***", c.String("db.host"))
+
+	is.Eq([]string{`["a.b"]`, "db.host", "db.port", "empty", "name", "secret.key", "tags.0", "tags.1", "yaml.a.0", "yaml.b"}, c.AllKeys())
+	is.Eq([]string{"host", "port"}, c.Scope("db").AllKeys())
+	is.Empty(New("empty").AllKeys())
+	is.NoErr(Walk(func(string, any, int) error { return nil }))
+}
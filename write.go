@@ -2,7 +2,10 @@ package config
 
 import (
 	"errors"
-	"strings"
+	"fmt"
+	"maps"
+	"reflect"
+	"strconv"
 
 	"github.com/gookit/goutil/maputil"
 )
@@ -20,12 +23,33 @@ func SetData(data map[string]any) {
 }
 
 // SetData for override the Config.Data
+//
+// The keys will be normalized on Options.KeyMatch is enabled, the conflict error can be got by Config.Error()
 func (c *Config) SetData(data map[string]any) {
+	if c.root != nil {
+		c.addError(c.root.set(c.scopePath, data, true, OnSetData))
+		return
+	}
+
+	data, err := c.normData(data)
+	if err != nil {
+		c.addError(err)
+	}
+
+	// copy the data for migrate alias keys, don't change the source data
+	if len(c.aliases()) > 0 && data != nil {
+		data = deepCopy(data).(map[string]any)
+	}
+
 	c.lock.Lock()
-	c.data = data
+	old := c.getData()
+	found := c.migrateAliases(data)
+	c.storeData(data)
+	c.queueEvent(OnSetData, nil, c.collectChanges(OnSetData, nil, old, true, data, true))
 	c.lock.Unlock()
 
-	c.fireHook(OnSetData)
+	c.warnDeprecated(found)
+	c.flushEvents()
 }
 
 // Set value by key. setByPath default is true
@@ -39,27 +63,424 @@ func (c *Config) Set(key string, val any, setByPath ...bool) (err error) {
 		return ErrReadonly
 	}
 
+	byPath := len(setByPath) == 0 || setByPath[0]
+	if c.root != nil {
+		if key = formatKey(key, string(c.opts.Delimiter)); key == "" {
+			return ErrKeyIsEmpty
+		}
+		return c.root.set(c.scopeKey(key, byPath), val, true, OnSetValue)
+	}
+	return c.set(key, val, byPath, OnSetValue)
+}
+
+// set value by key, and fire the event on success. will not fire on event is empty.
+func (c *Config) set(key string, val any, byPath bool, event string) (err error) {
+	if c.opts.Readonly {
+		return ErrReadonly
+	}
+
 	c.lock.Lock()
-	defer c.lock.Unlock()
+	defer func() {
+		c.lock.Unlock()
+		c.flushEvents()
+	}()
+	return c.setLocked(key, val, byPath, event)
+}
 
+// set value by key on a copy of the changed path, then publish the new data. must call it with the write lock.
+func (c *Config) setLocked(key string, val any, byPath bool, event string) (err error) {
 	sep := c.opts.Delimiter
 	if key = formatKey(key, string(sep)); key == "" {
 		return ErrKeyIsEmpty
 	}
+	key = c.resolveAlias(key)
 
-	defer c.fireHook(OnSetValue)
-	if strings.IndexByte(key, sep) == -1 {
-		c.data[key] = val
-		return
+	if val, err = c.normValue(val); err != nil {
+		return err
+	}
+
+	data := c.getData()
+	if data == nil {
+		data = make(map[string]any)
 	}
 
 	// disable set by path.
-	if len(setByPath) > 0 && !setByPath[0] {
-		c.data[key] = val
+	if !byPath {
+		key = c.opts.KeyMatch.Normalize(key)
+		old, ok := data[key]
+		data = maps.Clone(data)
+		data[key] = val
+		c.storeData(data)
+		c.queueEvent(event, []string{key}, c.collectChanges(event, []string{key}, old, ok, val, true))
 		return
 	}
 
+	keys, err := parsePathKeys(key, sep)
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, len(keys))
+	for i := range keys {
+		if !keys[i].index {
+			keys[i].name = c.opts.KeyMatch.Normalize(keys[i].name)
+		}
+		names[i] = keys[i].name
+	}
+
 	// set by path
-	keys := strings.Split(key, string(sep))
-	return maputil.SetByKeys(&c.data, keys, val)
+	old, findErr := findByKeys(data, names, 0, sep)
+	data = clonePath(data, keys, 0).(map[string]any)
+	if _, err = setByKeys(data, keys, 0, val, sep); err == nil {
+		c.storeData(data)
+		c.queueEvent(event, names, c.collectChanges(event, names, old, findErr == nil, val, true))
+	}
+	return
+}
+
+// Delete the value by key path. see Config.Delete()
+func Delete(key string) error { return dc.Delete(key) }
+
+// Delete the value by key path, support the nested maps and slices. eg: "db.host", "servers[0]"
+//
+// It will return *NotFoundError if the key does not exist. will fire the OnDeleteValue event on success.
+func (c *Config) Delete(key string) error {
+	deleted, err := c.deleteKeys([]string{key})
+	if err == nil && deleted == 0 {
+		err = &NotFoundError{Key: key}
+	}
+	return err
+}
+
+// DeleteMany delete the values by key paths. see Config.DeleteMany()
+func DeleteMany(keys ...string) error { return dc.DeleteMany(keys...) }
+
+// DeleteMany delete the values by key paths, the not exists keys are ignored.
+// will delete nothing if any key is invalid.
+func (c *Config) DeleteMany(keys ...string) error {
+	_, err := c.deleteKeys(keys)
+	return err
+}
+
+// delete the values by key paths, returns the number of deleted keys.
+func (c *Config) deleteKeys(keys []string) (int, error) {
+	if c.opts.Readonly {
+		return 0, ErrReadonly
+	}
+
+	sep := c.opts.Delimiter
+	if c.root != nil {
+		fullKeys := make([]string, len(keys))
+		for i, key := range keys {
+			if key = formatKey(key, string(sep)); key == "" {
+				return 0, ErrKeyIsEmpty
+			}
+			fullKeys[i] = c.scopeKey(key, true)
+		}
+		return c.root.deleteKeys(fullKeys)
+	}
+
+	// parse all keys first
+	paths := make([][]pathKey, len(keys))
+	for i, key := range keys {
+		if key = formatKey(key, string(sep)); key == "" {
+			return 0, ErrKeyIsEmpty
+		}
+
+		pks, err := parsePathKeys(c.resolveAlias(key), sep)
+		if err != nil {
+			return 0, err
+		}
+
+		for j := range pks {
+			if !pks[j].index {
+				pks[j].name = c.opts.KeyMatch.Normalize(pks[j].name)
+			}
+		}
+		paths[i] = pks
+	}
+
+	c.lock.Lock()
+	var deleted int
+	data := c.getData()
+	for _, pks := range paths {
+		names := make([]string, len(pks))
+		for i, pk := range pks {
+			names[i] = pk.name
+		}
+
+		old, err := findByKeys(data, names, 0, sep)
+		if err != nil {
+			continue
+		}
+
+		// delete on a copy of the path, the published data is not changed.
+		newData := clonePath(data, pks, 0).(map[string]any)
+		if _, ok := deleteByKeys(newData, pks, 0); ok {
+			data = newData
+			deleted++
+			c.queueEvent(OnDeleteValue, names, c.collectChanges(OnDeleteValue, names, old, true, nil, false))
+		}
+	}
+
+	if deleted > 0 {
+		c.storeData(data)
+		c.ClearCaches()
+	}
+	c.lock.Unlock()
+
+	c.flushEvents()
+	return deleted, nil
+}
+
+// copy the containers on the path of keys[idx:] for copy-on-write, the item is the container of keys[idx].
+// the leaf value is not copied. so the changes by setByKeys() and deleteByKeys() on the returned item
+// will not affect the source item, and the readers of the source item are safe.
+func clonePath(item any, keys []pathKey, idx int) any {
+	k := keys[idx]
+	isLeaf := idx == len(keys)-1
+
+	// fast path: map decoded from the config content
+	if mp, ok := item.(map[string]any); ok {
+		mp = maps.Clone(mp)
+		if child, ok := mp[k.name]; ok && !isLeaf {
+			mp[k.name] = clonePath(child, keys, idx+1)
+		}
+		return mp
+	}
+
+	rv := reflect.ValueOf(item)
+	switch rv.Kind() {
+	case reflect.Map:
+		if rv.IsNil() {
+			return item
+		}
+
+		mp := reflect.MakeMapWithSize(rv.Type(), rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			mp.SetMapIndex(iter.Key(), iter.Value())
+		}
+
+		keyType := rv.Type().Key()
+		if !isLeaf && (keyType.Kind() == reflect.String || keyType.Kind() == reflect.Interface) {
+			mk := reflect.ValueOf(k.name).Convert(keyType)
+			if child := mp.MapIndex(mk); child.IsValid() {
+				mp.SetMapIndex(mk, toReflectValue(clonePath(child.Interface(), keys, idx+1), rv.Type().Elem()))
+			}
+		}
+		return mp.Interface()
+	case reflect.Slice:
+		if rv.IsNil() {
+			return item
+		}
+
+		// NOTE: len == cap, so append on the copy will not write the source array.
+		list := reflect.MakeSlice(rv.Type(), rv.Len(), rv.Len())
+		reflect.Copy(list, rv)
+		if i, ok := sliceIndex(k.name, rv.Len()); ok && !isLeaf {
+			list.Index(i).Set(toReflectValue(clonePath(rv.Index(i).Interface(), keys, idx+1), rv.Type().Elem()))
+		}
+		return list.Interface()
+	}
+	return item
+}
+
+// delete the value from the item by keys[idx:], returns the new item and whether the value is found.
+func deleteByKeys(item any, keys []pathKey, idx int) (any, bool) {
+	k := keys[idx]
+	isLeaf := idx == len(keys)-1
+
+	// fast path: map decoded from the config content
+	if mp, ok := item.(map[string]any); ok {
+		child, ok := mp[k.name]
+		if !ok {
+			return item, false
+		}
+
+		if isLeaf {
+			delete(mp, k.name)
+			return mp, true
+		}
+
+		if child, ok = deleteByKeys(child, keys, idx+1); ok {
+			mp[k.name] = child
+		}
+		return mp, ok
+	}
+
+	rv := reflect.ValueOf(item)
+	switch rv.Kind() {
+	case reflect.Map:
+		keyType, elemType := rv.Type().Key(), rv.Type().Elem()
+		if keyType.Kind() != reflect.String && keyType.Kind() != reflect.Interface {
+			break
+		}
+
+		mk := reflect.ValueOf(k.name).Convert(keyType)
+		child := rv.MapIndex(mk)
+		if !child.IsValid() {
+			break
+		}
+
+		if isLeaf {
+			rv.SetMapIndex(mk, reflect.Value{})
+			return item, true
+		}
+
+		newChild, ok := deleteByKeys(child.Interface(), keys, idx+1)
+		if ok {
+			rv.SetMapIndex(mk, toReflectValue(newChild, elemType))
+		}
+		return item, ok
+	case reflect.Slice:
+		i, ok := sliceIndex(k.name, rv.Len())
+		if !ok {
+			break
+		}
+
+		// remove the element, will create a new slice.
+		if isLeaf {
+			list := reflect.MakeSlice(rv.Type(), 0, rv.Len()-1)
+			list = reflect.AppendSlice(list, rv.Slice(0, i))
+			return reflect.AppendSlice(list, rv.Slice(i+1, rv.Len())).Interface(), true
+		}
+
+		newChild, ok := deleteByKeys(rv.Index(i).Interface(), keys, idx+1)
+		if ok {
+			rv.Index(i).Set(toReflectValue(newChild, rv.Type().Elem()))
+		}
+		return item, ok
+	}
+	return item, false
+}
+
+// set value to the item by keys[idx:], returns the new item. keys is the full key path, use for the error.
+//
+// The missing containers will be created, it is a slice if the key is a bracket index. eg: "list[0]".
+// The typed map or slice(eg: []string) will be converted to map[string]any or []any if the value type is not matched.
+func setByKeys(item any, keys []pathKey, idx int, val any, sep byte) (any, error) {
+	if idx == len(keys) {
+		return val, nil
+	}
+
+	k := keys[idx]
+	isLeaf := idx == len(keys)-1
+
+	// create new map or slice
+	if item == nil {
+		if !k.index {
+			child, err := setByKeys(nil, keys, idx+1, val, sep)
+			return map[string]any{k.name: child}, err
+		}
+
+		if k.name != "0" {
+			return nil, setIndexError(keys, idx, sep)
+		}
+
+		// use typed slice for the value. eg: "list[0]" = "a" -> []string{"a"}
+		if isLeaf && val != nil {
+			rv := reflect.MakeSlice(reflect.SliceOf(reflect.TypeOf(val)), 0, 1)
+			return reflect.Append(rv, reflect.ValueOf(val)).Interface(), nil
+		}
+
+		child, err := setByKeys(nil, keys, idx+1, val, sep)
+		return []any{child}, err
+	}
+
+	// fast path: map decoded from the config content
+	if mp, ok := item.(map[string]any); ok {
+		child, err := setByKeys(mp[k.name], keys, idx+1, val, sep)
+		if err == nil {
+			mp[k.name] = child
+		}
+		return mp, err
+	}
+
+	rv := reflect.ValueOf(item)
+	switch rv.Kind() {
+	case reflect.Map:
+		keyType, elemType := rv.Type().Key(), rv.Type().Elem()
+		if keyType.Kind() != reflect.String && keyType.Kind() != reflect.Interface {
+			break
+		}
+
+		mk := reflect.ValueOf(k.name).Convert(keyType)
+		if elemType.Kind() == reflect.Interface {
+			var old any
+			if ov := rv.MapIndex(mk); ov.IsValid() {
+				old = ov.Interface()
+			}
+
+			child, err := setByKeys(old, keys, idx+1, val, sep)
+			if err == nil {
+				rv.SetMapIndex(mk, toReflectValue(child, elemType))
+			}
+			return item, err
+		}
+
+		// typed map. eg: map[string]string
+		if isLeaf && val != nil && reflect.TypeOf(val).AssignableTo(elemType) {
+			rv.SetMapIndex(mk, reflect.ValueOf(val))
+			return item, nil
+		}
+		return setByKeys(maputil.ToAnyMap(item), keys, idx, val, sep)
+	case reflect.Slice:
+		elemType := rv.Type().Elem()
+		i, ok := sliceIndex(k.name, rv.Len())
+		if !ok {
+			// allow append one element. eg: set "list[2]" on len(list) == 2
+			if k.name != strconv.Itoa(rv.Len()) {
+				return item, setIndexError(keys, idx, sep)
+			}
+
+			i = rv.Len()
+			rv = reflect.Append(rv, reflect.Zero(elemType))
+		}
+
+		if elemType.Kind() == reflect.Interface {
+			child, err := setByKeys(rv.Index(i).Interface(), keys, idx+1, val, sep)
+			if err == nil {
+				rv.Index(i).Set(toReflectValue(child, elemType))
+			}
+			return rv.Interface(), err
+		}
+
+		// typed slice. eg: []string
+		if isLeaf && val != nil && reflect.TypeOf(val).AssignableTo(elemType) {
+			rv.Index(i).Set(reflect.ValueOf(val))
+			return rv.Interface(), nil
+		}
+
+		list := make([]any, rv.Len())
+		for j := range list {
+			list[j] = rv.Index(j).Interface()
+		}
+		return setByKeys(list, keys, idx, val, sep)
+	}
+
+	return item, &PathError{Key: pathKeysToPath(keys, sep), Path: pathKeysToPath(keys[:idx], sep), Value: item}
+}
+
+func setIndexError(keys []pathKey, idx int, sep byte) error {
+	return fmt.Errorf("config: cannot set value for %q, invalid slice index %q", pathKeysToPath(keys, sep), keys[idx].name)
+}
+
+func toReflectValue(v any, typ reflect.Type) reflect.Value {
+	if v == nil {
+		return reflect.Zero(typ)
+	}
+	return reflect.ValueOf(v)
+}
+
+func pathKeysToPath(keys []pathKey, sep byte) string {
+	var path string
+	for _, pk := range keys {
+		if pk.index {
+			path += "[" + pk.name + "]"
+		} else {
+			path = joinPath(path, pk.name, sep)
+		}
+	}
+	return path
 }
@@ -201,3 +201,97 @@ func TestSet_fireEvent(t *testing.T) {
 	assert.Eq(t, "fire the: set.value", buf.String())
 	buf.Reset()
 }
+
+func TestConfig_Delete(t *testing.T) {
+	is := assert.New(t)
+
+	var events []string
+	c := New("test", EnableCache, WithHookFunc(func(event string, c *Config) {
+		events = append(events, event)
+	}))
+	err := c.LoadStrings(JSON, `{
+		"name": "app",
+		"db": {"host": "localhost", "port": 3306},
+		"servers": [{"host": "a"}, {"host": "b"}, {"host": "c"}],
+		"tags": ["x", "y"]
+	}`)
+	is.NoErr(err)
+	is.NoErr(c.Set("ints", []int{1, 2, 3}))
+	is.NoErr(c.Set("anyMap", map[any]any{"k1": "v1", "k2": "v2"}))
+	events = events[:0]
+
+	// cache is invalidated
+	is.Eq("localhost", c.String("db.host"))
+	is.NoErr(c.Delete("db.host"))
+	is.False(c.Exists("db.host"))
+	is.Eq("", c.String("db.host"))
+	is.Eq(map[string]any{"port": float64(3306)}, c.Get("db"))
+	is.Eq([]string{OnDeleteValue}, events)
+
+	// slices
+	is.NoErr(c.Delete("servers[1]"))
+	is.Eq("c", c.String("servers.1.host"))
+	is.NoErr(c.Delete("servers.-1.host"))
+	is.Eq(map[string]any{}, c.Get("servers.1"))
+	is.NoErr(c.Delete("ints.0"))
+	is.Eq([]int{2, 3}, c.Get("ints"))
+	is.NoErr(c.Delete("anyMap.k1"))
+	is.Eq(map[any]any{"k2": "v2"}, c.Get("anyMap"))
+
+	// not found
+	err = c.Delete("db.not-exist")
+	is.ErrIs(err, ErrNotFound)
+	is.ErrIs(c.Delete("name.sub"), ErrNotFound)
+	is.ErrIs(c.Delete("tags[5]"), ErrNotFound)
+	is.ErrIs(c.Delete(""), ErrKeyIsEmpty)
+	is.Len(events, 5)
+
+	// delete many
+	is.NoErr(c.DeleteMany("name", "tags[0]", "not-exist"))
+	is.False(c.Exists("name"))
+	is.Eq([]string{"y"}, c.Strings("tags"))
+	is.Len(events, 7)
+
+	// invalid key, delete nothing
+	is.Err(c.DeleteMany("db", "tags["))
+	is.True(c.Exists("db"))
+
+	// scope
+	is.NoErr(c.Scope("db").Delete("port"))
+	is.False(c.Exists("db.port"))
+
+	// readonly
+	c = New("test", Readonly)
+	is.ErrIs(c.Delete("name"), ErrReadonly)
+	is.ErrIs(Delete(""), ErrKeyIsEmpty)
+}
+
+func TestConfig_copyOnWrite(t *testing.T) {
+	is := assert.New(t)
+	c := New("test")
+	is.NoErr(c.LoadStrings(JSON, `{"name": "app", "db": {"host": "localhost", "ports": [3306, 3307]}, "log": {"level": "info"}}`))
+
+	data := c.Data()
+	db := c.Sub("db")
+	log := c.getData()["log"]
+
+	// the read data is not changed by the writes
+	is.NoErr(c.Set("db.host", "db.local"))
+	is.NoErr(c.Set("db.ports[1]", 3308))
+	is.NoErr(c.Set("name", "app2", false))
+	is.NoErr(c.Delete("db.ports[0]"))
+
+	// the not changed path is shared
+	is.Eq(fmt.Sprintf("%p", log), fmt.Sprintf("%p", c.getData()["log"]))
+	is.NoErr(c.LoadData(map[string]any{"db": map[string]any{"user": "root"}}))
+
+	is.Eq("localhost", db["host"])
+	is.Eq([]any{float64(3306), float64(3307)}, db["ports"])
+	is.Nil(db["user"])
+	is.Eq("app", data["name"])
+
+	is.Eq("db.local", c.String("db.host"))
+	is.Eq([]any{3308}, c.Get("db.ports"))
+	is.Eq("root", c.String("db.user"))
+	is.Eq("app2", c.String("name"))
+}
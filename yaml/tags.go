@@ -0,0 +1,393 @@
+package yaml
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/parser"
+)
+
+// There are builtin custom tags.
+//
+// The file tags(!file, !include, !secret) only allowed on the TagsDriver.
+// Relative path is resolved from the BaseDir on the top-level content,
+// and from the dir of the included file on the included content.
+const (
+	// TagEnv get value from ENV. eg: `!env HOME`, `!env APP_NAME|default`
+	TagEnv = "!env"
+	// TagFile read the file content as string value. eg: `!file ./cert.pem`
+	TagFile = "!file"
+	// TagInclude include and decode another YAML file. eg: `!include db.yaml`
+	TagInclude = "!include"
+	// TagSecret read the secret value from the SecretsDir. eg: `!secret db_password`
+	TagSecret = "!secret"
+)
+
+// DefaultSecretsDir default dir for read the secret value by `!secret` tag.
+const DefaultSecretsDir = "/run/secrets"
+
+// max include depth, for avoid include too deep.
+const maxIncludeDepth = 16
+
+// max nodes count of one document after expand the aliases, for avoid the "billion laughs" attack.
+const maxExpandNodes = 1 << 20
+
+// TagFunc resolve the value of a custom tag.
+//
+// The value is decoded from the tagged node. eg: `!upper app` will call fn("app")
+type TagFunc func(value any) (any, error)
+
+// RegisterTag register a custom tag resolver func to the driver.
+// it can also override the builtin tags.
+//
+// Usage:
+//
+//	yaml.Driver.RegisterTag("!upper", func(val any) (any, error) {
+//		return strings.ToUpper(fmt.Sprint(val)), nil
+//	})
+func (d *yamlDriver) RegisterTag(tag string, fn TagFunc) {
+	if !strings.HasPrefix(tag, "!") {
+		tag = "!" + tag
+	}
+
+	d.tagLock.Lock()
+	defer d.tagLock.Unlock()
+
+	if d.tags == nil {
+		d.tags = make(map[string]TagFunc)
+	}
+	d.tags[tag] = fn
+}
+
+func (d *yamlDriver) tagFunc(tag string) (TagFunc, bool) {
+	d.tagLock.RLock()
+	defer d.tagLock.RUnlock()
+
+	fn, ok := d.tags[tag]
+	return fn, ok
+}
+
+// decode the first document in blob, resolve custom tags, anchors and merge keys.
+func (d *yamlDriver) decodeValue(blob []byte) (any, error) {
+	r := &resolver{
+		d:       d,
+		baseDir: d.BaseDir,
+		anchors: make(map[string]*anchorValue),
+	}
+	return r.decode(blob)
+}
+
+// resolver convert yaml AST node to go value.
+//
+// Different from the goccy/go-yaml decoder, the alias node will be decoded to a new value,
+// so the resolved data not share maps or slices.
+type resolver struct {
+	d       *yamlDriver
+	baseDir string
+	// included files stack, for check include cycle
+	includes []string
+	anchors  map[string]*anchorValue
+	// resolved nodes count, include the expanded alias nodes and the included files.
+	nodes int
+}
+
+// the resolved value of an anchor
+type anchorValue struct {
+	val any
+	// nodes count of the value
+	size int
+}
+
+func (r *resolver) decode(blob []byte) (any, error) {
+	file, err := parser.ParseBytes(blob, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	// skip the directives. eg: "%YAML 1.2"
+	docs := file.Docs
+	for len(docs) > 0 {
+		if _, ok := docs[0].Body.(*ast.DirectiveNode); !ok {
+			break
+		}
+		docs = docs[1:]
+	}
+	if len(docs) == 0 {
+		return nil, nil
+	}
+	return r.value(docs[0].Body)
+}
+
+func (r *resolver) value(node ast.Node) (any, error) {
+	if r.nodes++; r.nodes > maxExpandNodes {
+		return nil, r.error(node, fmt.Errorf("the document nodes exceeds the limit %d", maxExpandNodes))
+	}
+
+	switch n := node.(type) {
+	case nil, *ast.CommentGroupNode:
+		return nil, nil
+	case *ast.DocumentNode:
+		return r.value(n.Body)
+	case *ast.AnchorNode:
+		start := r.nodes
+		val, err := r.value(n.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		// register after resolve value, so the recursive alias will be not found.
+		r.anchors[n.Name.GetToken().Value] = &anchorValue{val: val, size: r.nodes - start}
+		return val, nil
+	case *ast.AliasNode:
+		name := n.Value.GetToken().Value
+		anchor, ok := r.anchors[name]
+		if !ok {
+			return nil, r.error(n, fmt.Errorf("could not find alias %q", name))
+		}
+
+		// check the limit before copy the value
+		if r.nodes += anchor.size; r.nodes > maxExpandNodes {
+			return nil, r.error(n, fmt.Errorf("the document nodes exceeds the limit %d", maxExpandNodes))
+		}
+		return copyValue(anchor.val), nil
+	case *ast.TagNode:
+		return r.tagValue(n)
+	case *ast.MappingNode:
+		return r.mapValue(n.Values)
+	case *ast.MappingValueNode:
+		return r.mapValue([]*ast.MappingValueNode{n})
+	case *ast.MappingKeyNode:
+		return r.value(n.Value)
+	case *ast.SequenceNode:
+		list := make([]any, 0, len(n.Values))
+		for _, sn := range n.Values {
+			val, err := r.value(sn)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, val)
+		}
+		return list, nil
+	}
+
+	// scalar nodes
+	var val any
+	if err := yaml.NodeToValue(node, &val); err != nil {
+		return nil, err
+	}
+	return val, nil
+}
+
+// the explicit keys always override the merged keys(`<<: *base`), whatever the order.
+func (r *resolver) mapValue(values []*ast.MappingValueNode) (any, error) {
+	data := make(map[string]any, len(values))
+
+	var merged []map[string]any
+	for _, mv := range values {
+		if mv.Key.IsMergeKey() {
+			val, err := r.value(mv.Value)
+			if err != nil {
+				return nil, err
+			}
+
+			mps, err := toMergeMaps(val)
+			if err != nil {
+				return nil, r.error(mv, err)
+			}
+			merged = append(merged, mps...)
+			continue
+		}
+
+		key, err := r.value(mv.Key)
+		if err != nil {
+			return nil, err
+		}
+
+		val, err := r.value(mv.Value)
+		if err != nil {
+			return nil, err
+		}
+		data[fmt.Sprint(key)] = val
+	}
+
+	// the first merged map has the highest priority
+	for _, mp := range merged {
+		for k, v := range mp {
+			if _, ok := data[k]; !ok {
+				data[k] = v
+			}
+		}
+	}
+	return data, nil
+}
+
+func (r *resolver) tagValue(n *ast.TagNode) (any, error) {
+	tag := n.Start.Value
+	fn, ok := r.d.tagFunc(tag)
+	if !ok && !isBuiltinTag(tag) {
+		// standard tags(eg: !!str, !!binary) are handled by goccy/go-yaml, unknown tags are ignored.
+		switch n.Value.(type) {
+		case *ast.MappingNode, *ast.MappingValueNode, *ast.SequenceNode, *ast.AnchorNode, *ast.AliasNode, *ast.TagNode:
+			return r.value(n.Value)
+		}
+
+		var val any
+		if err := yaml.NodeToValue(n, &val); err != nil {
+			return nil, err
+		}
+		return val, nil
+	}
+
+	val, err := r.value(n.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret any
+	if ok {
+		ret, err = fn(val)
+	} else {
+		ret, err = r.builtinTag(tag, val)
+	}
+
+	if err != nil {
+		return nil, r.error(n, fmt.Errorf("resolve tag %s error: %w", tag, err))
+	}
+	return ret, nil
+}
+
+func (r *resolver) builtinTag(tag string, val any) (any, error) {
+	str, ok := val.(string)
+	if !ok || str == "" {
+		return nil, fmt.Errorf("the value must be a not empty string, but got %#v", val)
+	}
+
+	if tag != TagEnv && !r.d.fileTags {
+		return nil, fmt.Errorf("the file tag %s is not allowed, please use the TagsDriver", tag)
+	}
+
+	switch tag {
+	case TagEnv:
+		name, def, _ := strings.Cut(str, "|")
+		if ev, ok := os.LookupEnv(strings.TrimSpace(name)); ok {
+			return ev, nil
+		}
+		return strings.TrimSpace(def), nil
+	case TagFile:
+		bs, err := os.ReadFile(r.path(str))
+		if err != nil {
+			return nil, err
+		}
+		return string(bs), nil
+	case TagSecret:
+		if strings.ContainsAny(str, `/\`) || str == ".." {
+			return nil, fmt.Errorf("invalid secret name %q", str)
+		}
+
+		dir := r.d.SecretsDir
+		if dir == "" {
+			dir = DefaultSecretsDir
+		}
+
+		bs, err := os.ReadFile(filepath.Join(dir, str))
+		if err != nil {
+			return nil, err
+		}
+		return strings.TrimRight(string(bs), "\r\n"), nil
+	default: // TagInclude
+		return r.include(r.path(str))
+	}
+}
+
+func (r *resolver) include(file string) (any, error) {
+	if len(r.includes) >= maxIncludeDepth {
+		return nil, fmt.Errorf("include depth exceeds the limit %d", maxIncludeDepth)
+	}
+
+	for _, f := range r.includes {
+		if f == file {
+			return nil, fmt.Errorf("include cycle detected: %s", file)
+		}
+	}
+
+	bs, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	// the included file share the nodes count limit
+	sub := &resolver{
+		d:        r.d,
+		baseDir:  filepath.Dir(file),
+		includes: append(r.includes, file),
+		anchors:  make(map[string]*anchorValue),
+		nodes:    r.nodes,
+	}
+
+	val, err := sub.decode(bs)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+
+	r.nodes = sub.nodes
+	return val, nil
+}
+
+// get the file path, relative path will be resolved from the baseDir.
+func (r *resolver) path(file string) string {
+	if filepath.IsAbs(file) || r.baseDir == "" {
+		return file
+	}
+	return filepath.Join(r.baseDir, file)
+}
+
+func (r *resolver) error(node ast.Node, err error) error {
+	return fmt.Errorf("yaml: line %d: %w", node.GetToken().Position.Line, err)
+}
+
+// deep copy the map and slice value
+func copyValue(val any) any {
+	switch tv := val.(type) {
+	case map[string]any:
+		mp := make(map[string]any, len(tv))
+		for k, v := range tv {
+			mp[k] = copyValue(v)
+		}
+		return mp
+	case []any:
+		list := make([]any, len(tv))
+		for i, v := range tv {
+			list[i] = copyValue(v)
+		}
+		return list
+	}
+	return val
+}
+
+func isBuiltinTag(tag string) bool {
+	return tag == TagEnv || tag == TagFile || tag == TagInclude || tag == TagSecret
+}
+
+// the merge key value must be a map or a list of maps.
+func toMergeMaps(val any) ([]map[string]any, error) {
+	switch tv := val.(type) {
+	case map[string]any:
+		return []map[string]any{tv}, nil
+	case []any:
+		mps := make([]map[string]any, 0, len(tv))
+		for _, v := range tv {
+			mp, ok := v.(map[string]any)
+			if !ok {
+				return nil, errors.New("the merge key value must be a map or a list of maps")
+			}
+			mps = append(mps, mp)
+		}
+		return mps, nil
+	}
+	return nil, errors.New("the merge key value must be a map or a list of maps")
+}
@@ -0,0 +1,197 @@
+package yaml
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gookit/config/v2"
+	"github.com/gookit/goutil/testutil"
+	"github.com/gookit/goutil/testutil/assert"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestDriver_builtinTags(t *testing.T) {
+	is := assert.New(t)
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"db.yaml":  "host: !env DB_HOST|localhost\nport: 3306\nca: !file ca.pem\n",
+		"ca.pem":   "-----CERT-----\n",
+		"password": "secret123\n",
+	})
+
+	d := &yamlDriver{StdDriver: Driver.StdDriver, BaseDir: dir, SecretsDir: dir, fileTags: true}
+	c := config.NewEmpty("test")
+	c.AddDriver(d)
+
+	testutil.MockEnvValues(map[string]string{"APP_NAME": "my-app", "DB_HOST": "db.local"}, func() {
+		err := c.LoadStrings(config.Yaml, `
+name: !env APP_NAME
+env: !env APP_ENV | dev
+db: !include db.yaml
+password: !secret password
+str: !!str 123
+`)
+		is.NoErr(err)
+	})
+
+	is.Eq("my-app", c.String("name"))
+	is.Eq("dev", c.String("env"))
+	is.Eq("db.local", c.String("db.host"))
+	is.Eq(3306, c.Int("db.port"))
+	is.Eq("-----CERT-----\n", c.String("db.ca"))
+	is.Eq("secret123", c.String("password"))
+	is.Eq("123", c.Get("str"))
+}
+
+func TestDriver_tagErrors(t *testing.T) {
+	is := assert.New(t)
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"a.yaml": "b: !include b.yaml\n",
+		"b.yaml": "a: !include a.yaml\n",
+	})
+
+	d := &yamlDriver{StdDriver: Driver.StdDriver, BaseDir: dir, SecretsDir: dir, fileTags: true}
+	data := map[string]any{}
+
+	err := d.Decode([]byte("name: app\nfile: !file not-exists.txt\n"), &data)
+	is.ErrSubMsg(err, "yaml: line 2: resolve tag !file error")
+
+	err = d.Decode([]byte("key: !secret ../etc/passwd"), &data)
+	is.ErrSubMsg(err, `invalid secret name "../etc/passwd"`)
+
+	err = d.Decode([]byte("\n\nkey: !env [A, B]"), &data)
+	is.ErrSubMsg(err, "yaml: line 3: resolve tag !env error: the value must be a not empty string")
+
+	err = d.Decode([]byte("a: !include a.yaml"), &data)
+	is.ErrSubMsg(err, "include cycle detected")
+
+	err = d.Decode([]byte("a:\n  <<: [1, 2]\n"), &data)
+	is.ErrSubMsg(err, "yaml: line 2: the merge key value must be a map or a list of maps")
+}
+
+func TestDriver_fileTags(t *testing.T) {
+	is := assert.New(t)
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"db.yaml": "host: localhost\nca: !file certs/ca.pem\n",
+		"ca.pem":  "top-level\n",
+	})
+	is.NoErr(os.Mkdir(filepath.Join(dir, "certs"), 0755))
+	writeFiles(t, dir, map[string]string{"certs/ca.pem": "included\n"})
+
+	// the file tags are not allowed by default
+	data := map[string]any{}
+	for _, src := range []string{"a: !file ca.pem", "a: !include db.yaml", "a: !secret password"} {
+		is.ErrSubMsg(Driver.Decode([]byte(src), &data), "is not allowed, please use the TagsDriver")
+		is.ErrSubMsg(Decoder([]byte(src), &data), "is not allowed, please use the TagsDriver")
+	}
+
+	// the Decoder is same as Driver.Decode
+	testutil.MockEnvValues(map[string]string{"APP_NAME": "my-app"}, func() {
+		data = map[string]any{}
+		is.NoErr(Decoder([]byte("name: !env APP_NAME\nbase: &base {a: 1}\nsub: *base"), &data))
+		is.Eq("my-app", data["name"])
+		is.Eq(map[string]any{"a": uint64(1)}, data["sub"])
+	})
+
+	// top-level relative path from the BaseDir, included content from the dir of the included file
+	d := &yamlDriver{StdDriver: TagsDriver.StdDriver, BaseDir: dir, fileTags: true}
+	data = map[string]any{}
+	is.NoErr(d.Decode([]byte("ca: !file ca.pem\ndb: !include db.yaml"), &data))
+	is.Eq("top-level\n", data["ca"])
+	is.Eq("included\n", data["db"].(map[string]any)["ca"])
+}
+
+func TestDriver_RegisterTag(t *testing.T) {
+	is := assert.New(t)
+
+	d := &yamlDriver{StdDriver: Driver.StdDriver}
+	d.RegisterTag("upper", func(val any) (any, error) {
+		return strings.ToUpper(val.(string)), nil
+	})
+	d.RegisterTag("!len", func(val any) (any, error) {
+		return len(val.([]any)), nil
+	})
+
+	data := map[string]any{}
+	err := d.Decode([]byte("name: !upper app\nnum: !len [a, b, c]\nother: !unknown val"), &data)
+	is.NoErr(err)
+	is.Eq("APP", data["name"])
+	is.Eq(3, data["num"])
+	is.Eq("val", data["other"])
+}
+
+func TestDriver_anchorsAndMergeKeys(t *testing.T) {
+	is := assert.New(t)
+
+	c := config.NewEmpty("test")
+	c.AddDriver(Driver)
+	err := c.LoadStrings(config.Yaml, `
+base: &base
+  host: localhost
+  port: 3306
+  tags: [a, b]
+extra: &extra
+  port: 3307
+  debug: true
+dev:
+  port: 3308
+  <<: [*base, *extra]
+prod:
+  <<: *base
+  host: db.prod
+`)
+	is.NoErr(err)
+	is.Eq("localhost", c.String("dev.host"))
+	is.Eq(3308, c.Int("dev.port"))
+	is.True(c.Bool("dev.debug"))
+	is.Eq("db.prod", c.String("prod.host"))
+	is.Eq(3306, c.Int("prod.port"))
+
+	// aliases are decoded to new values, not share the data
+	is.NoErr(c.Set("prod.tags.0", "c"))
+	is.Eq([]string{"a", "b"}, c.Strings("base.tags"))
+	is.Eq([]string{"a", "b"}, c.Strings("dev.tags"))
+}
+
+func TestDriver_aliasExpandLimit(t *testing.T) {
+	is := assert.New(t)
+
+	// "billion laughs": 10 levels, each level has 10 aliases of the previous level
+	var sb strings.Builder
+	sb.WriteString("l0: &l0 [lol, lol, lol, lol, lol, lol, lol, lol, lol, lol]\n")
+	for i := 1; i < 10; i++ {
+		sb.WriteString(fmt.Sprintf("l%d: &l%d [", i, i))
+		for j := 0; j < 10; j++ {
+			if j > 0 {
+				sb.WriteString(", ")
+			}
+			sb.WriteString(fmt.Sprintf("*l%d", i-1))
+		}
+		sb.WriteString("]\n")
+	}
+
+	start := time.Now()
+	data := map[string]any{}
+	err := Driver.Decode([]byte(sb.String()), &data)
+	is.ErrSubMsg(err, "the document nodes exceeds the limit")
+	is.True(time.Since(start) < 5*time.Second)
+
+	// nested aliases under the limit
+	data = map[string]any{}
+	err = Driver.Decode([]byte("a: &a [x, y]\nb: &b [*a, *a]\nc: [*b, *b]\n"), &data)
+	is.NoErr(err)
+	is.Eq([]any{[]any{"x", "y"}, []any{"x", "y"}}, data["c"].([]any)[1])
+}
@@ -6,15 +6,217 @@ Usage please see example:
 package yaml
 
 import (
+	"bytes"
+	"fmt"
+	"sync"
+
 	"github.com/goccy/go-yaml"
 	"github.com/gookit/config/v2"
 )
 
-// Decoder the yaml content decoder
-var Decoder config.Decoder = yaml.Unmarshal
-
 // Encoder the yaml content encoder
 var Encoder config.Encoder = yaml.Marshal
 
-// Driver for yaml
-var Driver = config.NewDriver(config.Yaml, Decoder, Encoder).WithAliases(config.Yml)
+// Driver for yaml. will resolve anchors, merge keys, the !env tag and the custom tags.
+//
+// The file tags(!file, !include, !secret) are not allowed, please use the TagsDriver.
+var Driver = &yamlDriver{StdDriver: config.NewDriver(config.Yaml, yaml.Unmarshal, Encoder).WithAliases(config.Yml)}
+
+// Decoder the yaml content decoder, same as Driver.Decode
+var Decoder config.Decoder = Driver.Decode
+
+// TagsDriver for yaml, same as Driver but allow the file tags: !file, !include, !secret
+//
+// NOTE: the file tags can read any local file, don't use it for load untrusted content. eg: remote url
+//
+// Usage:
+//
+//	yaml.TagsDriver.BaseDir = "/path/to/config"
+//	config.AddDriver(yaml.TagsDriver)
+var TagsDriver = &yamlDriver{
+	StdDriver: config.NewDriver(config.Yaml, yaml.Unmarshal, Encoder).WithAliases(config.Yml),
+	fileTags:  true,
+}
+
+// TagsDecoder the yaml content decoder with the file tags, same as TagsDriver.Decode
+var TagsDecoder config.Decoder = TagsDriver.Decode
+
+type yamlDriver struct {
+	*config.StdDriver
+	// BaseDir for resolve the relative path of the !include and !file tags on the top-level content.
+	// default is empty, will use the current working dir.
+	//
+	// TIP: on the included file, the relative path is always resolved from the dir of the included file.
+	BaseDir string
+	// SecretsDir for read the secret value by the !secret tag. default is DefaultSecretsDir
+	SecretsDir string
+	// allow the file tags: !file, !include, !secret
+	fileTags bool
+
+	tagLock sync.RWMutex
+	// custom tag resolvers. key is tag name, eg: "!upper"
+	tags map[string]TagFunc
+}
+
+// Decode the first document in content, will resolve custom tags, anchors and merge keys.
+//
+// Builtin tags: !env, and !file, !include, !secret on the TagsDriver. add custom tag by RegisterTag()
+func (d *yamlDriver) Decode(blob []byte, v any) error {
+	val, err := d.decodeValue(blob)
+	if err != nil || val == nil {
+		return err
+	}
+
+	if ptr, ok := v.(*map[string]any); ok {
+		mp, ok := val.(map[string]any)
+		if !ok {
+			return fmt.Errorf("yaml: cannot decode %T to %T", val, mp)
+		}
+
+		if *ptr == nil {
+			*ptr = mp
+			return nil
+		}
+		for k, sv := range mp {
+			(*ptr)[k] = sv
+		}
+		return nil
+	}
+
+	// other types: convert by encode and decode again
+	bs, err := yaml.Marshal(val)
+	if err != nil {
+		return err
+	}
+	return yaml.Unmarshal(bs, v)
+}
+
+// GetDecoder get the decoder func, it supports custom tags.
+func (d *yamlDriver) GetDecoder() config.Decoder { return d.Decode }
+
+// EncodeWith encode data with config.EncodeOptions.
+//
+// Indent is supported, only use the length of the indent string. keys are always sorted.
+func (d *yamlDriver) EncodeWith(v any, opts *config.EncodeOptions) ([]byte, error) {
+	if opts == nil || opts.Indent == "" {
+		return d.Encode(v)
+	}
+	return yaml.MarshalWithOptions(v, yaml.Indent(len(opts.Indent)))
+}
+
+// DecodeDocs decode multi documents content, the documents are separated by `---`.
+//
+// Empty documents will be skipped, the document number in the error is the position in the content.
+func (d *yamlDriver) DecodeDocs(blob []byte) ([]map[string]any, error) {
+	var docs []map[string]any
+	for i, doc := range splitDocuments(blob) {
+		if isEmptyDocument(doc.body) {
+			continue
+		}
+
+		data := make(map[string]any)
+		if err := d.Decode(doc.source(), &data); err != nil {
+			return nil, fmt.Errorf("yaml: decode document #%d(start at line %d) error: %w", i, doc.line, err)
+		}
+		docs = append(docs, data)
+	}
+	return docs, nil
+}
+
+// EncodeDocs encode multi documents to one content, the documents are separated by `---`.
+func (d *yamlDriver) EncodeDocs(docs []any) ([]byte, error) {
+	var buf bytes.Buffer
+	for i, doc := range docs {
+		bs, err := d.Encode(doc)
+		if err != nil {
+			return nil, err
+		}
+
+		if i > 0 {
+			buf.WriteString("---\n")
+		}
+		buf.Write(bs)
+	}
+	return buf.Bytes(), nil
+}
+
+type document struct {
+	line int // start line number of the document
+	// the directives before the document start marker. eg: "%YAML 1.2"
+	directives []byte
+	body       []byte
+	// the document is started by the marker `---`
+	started bool
+}
+
+// get the source of the document, the directives are kept.
+func (doc *document) source() []byte {
+	if len(doc.directives) == 0 {
+		return doc.body
+	}
+
+	src := append([]byte(nil), doc.directives...)
+	src = append(src, "---\n"...)
+	return append(src, doc.body...)
+}
+
+// split content to documents by the document markers `---` and `...` at the line start.
+// the directives(eg: "%YAML 1.2") belong to the next document.
+//
+// TIP: don't use the yaml.Decoder or parser of goccy/go-yaml for this,
+// they will drop the documents after an empty document, and the parser cannot handle %TAG directive.
+func splitDocuments(blob []byte) []document {
+	var docs []document
+	cur := document{line: 1}
+	add := func() {
+		// the content before the first marker or after the end marker is a document only if not empty.
+		if cur.started || !isEmptyDocument(cur.body) {
+			docs = append(docs, cur)
+		}
+	}
+
+	var directives []byte
+	lines := bytes.SplitAfter(blob, []byte("\n"))
+	for i, line := range lines {
+		switch {
+		case len(line) > 0 && line[0] == '%':
+			directives = append(directives, line...)
+		case isMarker(line, "---"):
+			add()
+			// the document starts at the first directive line
+			start := i + 1 - bytes.Count(directives, []byte("\n"))
+			cur = document{line: start, directives: directives, started: true}
+			directives = nil
+			// content after the start marker. eg: "--- {a: 1}"
+			cur.body = append(cur.body, bytes.TrimLeft(line[3:], " \t")...)
+		case isMarker(line, "..."):
+			add()
+			cur = document{line: i + 2}
+		default:
+			cur.body = append(cur.body, line...)
+		}
+	}
+
+	add()
+	return docs
+}
+
+func isMarker(line []byte, marker string) bool {
+	if !bytes.HasPrefix(line, []byte(marker)) {
+		return false
+	}
+
+	rest := line[len(marker):]
+	return len(rest) == 0 || rest[0] == ' ' || rest[0] == '\t' || rest[0] == '\n' || rest[0] == '\r'
+}
+
+// check the document only contains blank lines, comments or directives.
+func isEmptyDocument(body []byte) bool {
+	for _, line := range bytes.Split(body, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if len(line) > 0 && line[0] != '#' && line[0] != '%' {
+			return false
+		}
+	}
+	return true
+}
@@ -166,3 +166,109 @@ command: ${ APP_COMMAND | app:run }
 	is.Eq("", config.Getenv("APP_COMMAND"))
 	is.Eq("app:run", c.String("command"))
 }
+
+func TestDriver_EncodeWith(t *testing.T) {
+	is := assert.New(t)
+
+	c := config.NewEmpty("test")
+	c.AddDriver(Driver)
+	is.NoErr(c.LoadData(map[string]any{"db": map[string]any{"host": "localhost"}}))
+
+	buf := new(bytes.Buffer)
+	_, err := c.DumpTo(buf, config.Yaml, config.EncodeIndent("    "))
+	is.NoErr(err)
+	is.Eq("db:\n    host: localhost\n\n", buf.String())
+}
+
+func TestDriver_multiDocs(t *testing.T) {
+	is := assert.New(t)
+	src := `# first
+name: app
+db:
+  host: localhost
+  port: 3306
+---
+# empty document
+---
+--- {name: app2, db: {port: 3307}}
+...
+debug: true
+`
+
+	docs, err := Driver.DecodeDocs([]byte(src))
+	is.NoErr(err)
+	is.Len(docs, 3)
+	is.Eq("app2", docs[1]["name"])
+	is.Eq(true, docs[2]["debug"])
+
+	// merge documents in order
+	c := config.NewEmpty("test")
+	c.AddDriver(Driver)
+	is.NoErr(c.LoadStrings(config.Yaml, src))
+	is.Eq("app2", c.String("name"))
+	is.Eq("localhost", c.String("db.host"))
+	is.Eq(3307, c.Int("db.port"))
+	is.True(c.Bool("debug"))
+
+	// LoadDocuments
+	cs, err := c.LoadDocuments(config.Yaml, []byte(src))
+	is.NoErr(err)
+	is.Len(cs, 3)
+	is.Eq(3306, cs[0].Int("db.port"))
+	is.False(cs[2].Exists("name"))
+
+	buf := new(bytes.Buffer)
+	_, err = config.DumpDocuments(buf, config.Yaml, cs[1:]...)
+	is.NoErr(err)
+	is.Eq("db:\n  port: 3307\nname: app2\n---\ndebug: true\n", buf.String())
+
+	// error with document start line
+	_, err = Driver.DecodeDocs([]byte("name: app\n---\nname: [app\n"))
+	is.ErrSubMsg(err, "yaml: decode document #1(start at line 2) error")
+	// the empty documents are counted
+	_, err = Driver.DecodeDocs([]byte("name: app\n---\n---\nname: [app\n"))
+	is.ErrSubMsg(err, "yaml: decode document #2(start at line 3) error")
+	_, err = Driver.DecodeDocs([]byte("---\nname: [app\n"))
+	is.ErrSubMsg(err, "yaml: decode document #0(start at line 1) error")
+
+	// the hooks are not fired on LoadDocuments
+	var events []string
+	c = config.NewEmpty("test", config.WithHookFunc(func(event string, c *config.Config) {
+		events = append(events, event)
+	}))
+	c.AddDriver(Driver)
+	cs, err = c.LoadDocuments(config.Yaml, []byte(src))
+	is.NoErr(err)
+	is.Len(cs, 3)
+	is.Empty(events)
+}
+
+func TestDriver_multiDocs_directives(t *testing.T) {
+	is := assert.New(t)
+	src := `name: app
+...
+%YAML 1.2
+---
+name: app2
+---
+name: app3
+`
+
+	docs, err := Driver.DecodeDocs([]byte(src))
+	is.NoErr(err)
+	is.Len(docs, 3)
+	is.Eq("app2", docs[1]["name"])
+	is.Eq("app3", docs[2]["name"])
+
+	// the directives are kept with the next document
+	parts := splitDocuments([]byte(src))
+	is.Len(parts, 3)
+	is.Eq("%YAML 1.2\n", string(parts[1].directives))
+	is.Eq(3, parts[1].line)
+	is.Empty(parts[0].directives)
+
+	// decode single document with directive
+	mp := map[string]any{}
+	is.NoErr(Driver.Decode([]byte("%YAML 1.2\n---\nname: app\n"), &mp))
+	is.Eq("app", mp["name"])
+}